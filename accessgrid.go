@@ -14,6 +14,7 @@ type Client struct {
 	client      *client.Client
 	AccessCards *services.AccessCardsService
 	Console     *services.ConsoleService
+	Webhooks    *services.WebhooksService
 }
 
 // NewClient creates a new AccessGrid API client
@@ -27,6 +28,7 @@ func NewClient(accountID, secretKey string, options ...client.Option) (*Client,
 		client:      c,
 		AccessCards: services.NewAccessCardsService(c),
 		Console:     services.NewConsoleService(c),
+		Webhooks:    services.NewWebhooksService(c),
 	}, nil
 }
 
@@ -48,9 +50,21 @@ type (
 	// UnifiedAccessPass represents a response from issuing to a template pair
 	UnifiedAccessPass = models.UnifiedAccessPass
 
+	// UnionValue wraps a Union so it can be round-tripped through JSON
+	UnionValue = models.UnionValue
+
 	// Device represents a device associated with an access pass
 	Device = models.Device
 
+	// DevicePlatform identifies the wallet platform a device belongs to
+	DevicePlatform = models.DevicePlatform
+
+	// DeviceType identifies the kind of hardware a pass is installed on
+	DeviceType = models.DeviceType
+
+	// DeviceStatus represents the lifecycle state of a device
+	DeviceStatus = models.DeviceStatus
+
 	// Card represents an NFC key or access pass
 	Card = models.Card
 
@@ -66,6 +80,36 @@ type (
 	// ListKeysParams defines parameters for filtering cards
 	ListKeysParams = models.ListKeysParams
 
+	// ReissueParams defines overrides applied when reissuing a card
+	ReissueParams = models.ReissueParams
+
+	// ReissueReport describes every step taken while reissuing a card
+	ReissueReport = models.ReissueReport
+
+	// OffboardParams defines how an employee's cards are revoked
+	OffboardParams = models.OffboardParams
+
+	// OffboardReport is an audit trail of the actions taken while offboarding
+	OffboardReport = models.OffboardReport
+
+	// CardLookup holds the cards matching a lookup, grouped by template
+	CardLookup = models.CardLookup
+
+	// LookupError is returned when a lookup expecting one card finds none or several
+	LookupError = models.LookupError
+
+	// TemplatePlatform is the wallet a template issues passes to
+	TemplatePlatform = models.TemplatePlatform
+
+	// TemplateUseCase describes what passes issued from a template are for
+	TemplateUseCase = models.TemplateUseCase
+
+	// TemplateProtocol is the NFC protocol passes from a template use
+	TemplateProtocol = models.TemplateProtocol
+
+	// ValidationError lists every invalid field found by a Validate method
+	ValidationError = models.ValidationError
+
 	// Template represents a card template
 	Template = models.Template
 
@@ -81,9 +125,114 @@ type (
 	// UpdateTemplateParams defines parameters for updating an existing template
 	UpdateTemplateParams = models.UpdateTemplateParams
 
+	// CloneTemplateParams defines overrides applied when cloning a template
+	CloneTemplateParams = models.CloneTemplateParams
+
+	// CloneTemplateResult describes a cloned template
+	CloneTemplateResult = models.CloneTemplateResult
+
+	// DeleteTemplateParams controls how SafeDeleteTemplate treats remaining cards
+	DeleteTemplateParams = models.DeleteTemplateParams
+
+	// TemplateDeletionReport describes the cards affected by deleting a template
+	TemplateDeletionReport = models.TemplateDeletionReport
+
+	// TemplatePair links an Apple and a Google template
+	TemplatePair = models.TemplatePair
+
+	// CreateTemplatePairParams defines parameters for creating a template pair
+	CreateTemplatePairParams = models.CreateTemplatePairParams
+
+	// TemplateVersion is a snapshot of a template taken before it was updated
+	TemplateVersion = models.TemplateVersion
+
+	// TemplateChange is a single field that differs between two template versions
+	TemplateChange = models.TemplateChange
+
 	// EventLogFilters defines parameters for filtering event logs
 	EventLogFilters = models.EventLogFilters
 
+	// SortOrder controls the order in which event logs are returned
+	SortOrder = models.SortOrder
+
 	// Event represents an event in the event log
 	Event = models.Event
+
+	// EventType identifies the kind of an event log entry
+	EventType = models.EventType
+
+	// EventDetails is the structured payload of an event
+	EventDetails = models.EventDetails
+
+	// EventCheckpoint records how far an event watch has read for one template
+	EventCheckpoint = models.EventCheckpoint
+
+	// Webhook represents a webhook endpoint subscription
+	Webhook = models.Webhook
+
+	// WebhookEventType identifies the kind of webhook event
+	WebhookEventType = models.WebhookEventType
+
+	// CreateWebhookParams defines parameters for creating a webhook endpoint
+	CreateWebhookParams = models.CreateWebhookParams
+
+	// UpdateWebhookParams defines parameters for updating a webhook endpoint
+	UpdateWebhookParams = models.UpdateWebhookParams
+
+	// WebhookTestResult is the outcome of sending a test event to an endpoint
+	WebhookTestResult = models.WebhookTestResult
+)
+
+// Export model constants for easy access
+const (
+	DevicePlatformApple   = models.DevicePlatformApple
+	DevicePlatformGoogle  = models.DevicePlatformGoogle
+	DevicePlatformAndroid = models.DevicePlatformAndroid
+
+	DeviceTypeIPhone = models.DeviceTypeIPhone
+	DeviceTypeWatch  = models.DeviceTypeWatch
+	DeviceTypePhone  = models.DeviceTypePhone
+	DeviceTypeTablet = models.DeviceTypeTablet
+
+	DeviceStatusActive    = models.DeviceStatusActive
+	DeviceStatusSuspended = models.DeviceStatusSuspended
+	DeviceStatusUnlinked  = models.DeviceStatusUnlinked
+
+	StateMixed = models.StateMixed
+
+	CardStateActive    = models.CardStateActive
+	CardStateSuspended = models.CardStateSuspended
+	CardStateDeleted   = models.CardStateDeleted
+
+	EventTypeProvision     = models.EventTypeProvision
+	EventTypeLinkOpened    = models.EventTypeLinkOpened
+	EventTypeInstall       = models.EventTypeInstall
+	EventTypeUninstall     = models.EventTypeUninstall
+	EventTypeUpdate        = models.EventTypeUpdate
+	EventTypeSuspend       = models.EventTypeSuspend
+	EventTypeResume        = models.EventTypeResume
+	EventTypeUnlink        = models.EventTypeUnlink
+	EventTypeDelete        = models.EventTypeDelete
+	EventTypeDeviceAdded   = models.EventTypeDeviceAdded
+	EventTypeDeviceRemoved = models.EventTypeDeviceRemoved
+	EventTypeTap           = models.EventTypeTap
+
+	SortAscending  = models.SortAscending
+	SortDescending = models.SortDescending
+
+	TemplatePlatformApple   = models.TemplatePlatformApple
+	TemplatePlatformGoogle  = models.TemplatePlatformGoogle
+	TemplatePlatformAndroid = models.TemplatePlatformAndroid
+
+	UseCaseEmployeeBadge = models.UseCaseEmployeeBadge
+	UseCaseStudentID     = models.UseCaseStudentID
+	UseCaseHotel         = models.UseCaseHotel
+	UseCaseResidential   = models.UseCaseResidential
+
+	ProtocolDESFire  = models.ProtocolDESFire
+	ProtocolSEOS     = models.ProtocolSEOS
+	ProtocolSmartTap = models.ProtocolSmartTap
+
+	TemplateDeletionMigrate = models.TemplateDeletionMigrate
+	TemplateDeletionSuspend = models.TemplateDeletionSuspend
 )
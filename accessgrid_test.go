@@ -68,4 +68,8 @@ func TestClientOptions(t *testing.T) {
 	if client.Console == nil {
 		t.Error("Expected Console service to be initialized")
 	}
+
+	if client.Webhooks == nil {
+		t.Error("Expected Webhooks service to be initialized")
+	}
 }
@@ -0,0 +1,306 @@
+// Package accessgridtest provides an in-process stand-in for the AccessGrid
+// API, built on httptest, for testing code that uses the SDK without network
+// access. It currently implements the webhook endpoints, including signed
+// test deliveries.
+package accessgridtest
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Access-Grid/accessgrid-go/models"
+	"github.com/Access-Grid/accessgrid-go/webhook"
+)
+
+// Server is a fake AccessGrid API. Point a client at it with
+// accessgrid.WithBaseURL(server.URL).
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	webhooks map[string]models.Webhook
+	secrets  map[string]string
+	nextID   int
+	now      func() time.Time
+}
+
+// NewServer starts a new Server. Call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		webhooks: make(map[string]models.Webhook),
+		secrets:  make(map[string]string),
+		now:      time.Now,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/webhooks", s.createWebhook)
+	mux.HandleFunc("GET /v1/webhooks", s.listWebhooks)
+	mux.HandleFunc("GET /v1/webhooks/{id}", s.getWebhook)
+	mux.HandleFunc("PATCH /v1/webhooks/{id}", s.updateWebhook)
+	mux.HandleFunc("DELETE /v1/webhooks/{id}", s.deleteWebhook)
+	mux.HandleFunc("POST /v1/webhooks/{id}/test", s.testWebhook)
+	mux.HandleFunc("POST /v1/webhooks/{id}/rotate-secret", s.rotateWebhookSecret)
+
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// WebhookSecret returns the current signing secret of a webhook endpoint
+func (s *Server) WebhookSecret(webhookID string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.secrets[webhookID]
+}
+
+// Deliver sends a signed event to every enabled endpoint subscribed to its
+// type, the way AccessGrid delivers webhooks, and returns the result for
+// each endpoint keyed by webhook ID
+func (s *Server) Deliver(ctx context.Context, eventType models.WebhookEventType, data interface{}) (map[string]models.WebhookTestResult, error) {
+	s.mu.Lock()
+	var targets []models.Webhook
+	for _, hook := range s.webhooks {
+		if hook.Enabled && subscribed(hook, eventType) {
+			targets = append(targets, hook)
+		}
+	}
+	s.mu.Unlock()
+
+	results := make(map[string]models.WebhookTestResult, len(targets))
+	for _, hook := range targets {
+		result, err := s.deliver(ctx, hook.ID, eventType, data)
+		if err != nil {
+			return results, err
+		}
+		results[hook.ID] = result
+	}
+	return results, nil
+}
+
+func (s *Server) deliver(ctx context.Context, webhookID string, eventType models.WebhookEventType, data interface{}) (models.WebhookTestResult, error) {
+	s.mu.Lock()
+	hook, ok := s.webhooks[webhookID]
+	secret := s.secrets[webhookID]
+	s.mu.Unlock()
+	if !ok {
+		return models.WebhookTestResult{}, fmt.Errorf("webhook %s not found", webhookID)
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return models.WebhookTestResult{}, err
+	}
+	sentAt := s.now()
+	body, err := json.Marshal(webhook.Event{
+		ID:        "whevt_" + randomHex(8),
+		Type:      eventType,
+		CreatedAt: sentAt.UTC(),
+		Data:      payload,
+	})
+	if err != nil {
+		return models.WebhookTestResult{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return models.WebhookTestResult{Error: err.Error()}, nil
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhook.TimestampHeader, strconv.FormatInt(sentAt.Unix(), 10))
+	req.Header.Set(webhook.SignatureHeader, webhook.Sign(secret, sentAt, body))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return models.WebhookTestResult{Error: err.Error()}, nil
+	}
+	resp.Body.Close()
+
+	return models.WebhookTestResult{
+		Delivered:  resp.StatusCode >= 200 && resp.StatusCode < 300,
+		StatusCode: resp.StatusCode,
+	}, nil
+}
+
+func (s *Server) createWebhook(w http.ResponseWriter, r *http.Request) {
+	var params models.CreateWebhookParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if params.URL == "" || len(params.EventTypes) == 0 {
+		writeError(w, http.StatusUnprocessableEntity, "url and event_types are required")
+		return
+	}
+
+	s.mu.Lock()
+	s.nextID++
+	now := s.now().UTC()
+	hook := models.Webhook{
+		ID:          fmt.Sprintf("wh_%d", s.nextID),
+		URL:         params.URL,
+		Description: params.Description,
+		EventTypes:  params.EventTypes,
+		Enabled:     true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	s.webhooks[hook.ID] = hook
+	s.secrets[hook.ID] = newSecret()
+	hook.Secret = s.secrets[hook.ID]
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusCreated, hook)
+}
+
+func (s *Server) listWebhooks(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	hooks := make([]models.Webhook, 0, len(s.webhooks))
+	for _, hook := range s.webhooks {
+		hooks = append(hooks, hook)
+	}
+	s.mu.Unlock()
+
+	sort.Slice(hooks, func(i, j int) bool {
+		if !hooks[i].CreatedAt.Equal(hooks[j].CreatedAt) {
+			return hooks[i].CreatedAt.Before(hooks[j].CreatedAt)
+		}
+		return hooks[i].ID < hooks[j].ID
+	})
+	writeJSON(w, http.StatusOK, map[string][]models.Webhook{"webhooks": hooks})
+}
+
+func (s *Server) getWebhook(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	hook, ok := s.webhooks[r.PathValue("id")]
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "webhook not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, hook)
+}
+
+func (s *Server) updateWebhook(w http.ResponseWriter, r *http.Request) {
+	var params models.UpdateWebhookParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hook, ok := s.webhooks[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "webhook not found")
+		return
+	}
+	if params.URL != "" {
+		hook.URL = params.URL
+	}
+	if params.Description != "" {
+		hook.Description = params.Description
+	}
+	if len(params.EventTypes) > 0 {
+		hook.EventTypes = params.EventTypes
+	}
+	if params.Enabled != nil {
+		hook.Enabled = *params.Enabled
+	}
+	hook.UpdatedAt = s.now().UTC()
+	s.webhooks[hook.ID] = hook
+
+	writeJSON(w, http.StatusOK, hook)
+}
+
+func (s *Server) deleteWebhook(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	if _, ok := s.webhooks[id]; !ok {
+		writeError(w, http.StatusNotFound, "webhook not found")
+		return
+	}
+	delete(s.webhooks, id)
+	delete(s.secrets, id)
+	writeJSON(w, http.StatusOK, map[string]string{})
+}
+
+func (s *Server) testWebhook(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	s.mu.Lock()
+	_, ok := s.webhooks[id]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "webhook not found")
+		return
+	}
+
+	result, err := s.deliver(r.Context(), id, models.WebhookEventTest, map[string]string{"webhook_id": id})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) rotateWebhookSecret(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hook, ok := s.webhooks[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "webhook not found")
+		return
+	}
+	s.secrets[hook.ID] = newSecret()
+	hook.UpdatedAt = s.now().UTC()
+	s.webhooks[hook.ID] = hook
+
+	hook.Secret = s.secrets[hook.ID]
+	writeJSON(w, http.StatusOK, hook)
+}
+
+func subscribed(hook models.Webhook, eventType models.WebhookEventType) bool {
+	if eventType == models.WebhookEventTest {
+		return true
+	}
+	for _, t := range hook.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func newSecret() string {
+	return "whsec_" + randomHex(24)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"message": message})
+}
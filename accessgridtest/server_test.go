@@ -0,0 +1,61 @@
+package accessgridtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Access-Grid/accessgrid-go/models"
+	"github.com/Access-Grid/accessgrid-go/webhook"
+)
+
+func TestServer_Deliver(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	var received []string
+	var handler *webhook.Handler
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r)
+	}))
+	defer endpoint.Close()
+
+	create := func(eventType models.WebhookEventType) models.Webhook {
+		body, _ := json.Marshal(models.CreateWebhookParams{URL: endpoint.URL, EventTypes: []models.WebhookEventType{eventType}})
+		resp, err := http.Post(server.URL+"/v1/webhooks", "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("create webhook error = %v", err)
+		}
+		defer resp.Body.Close()
+		var hook models.Webhook
+		json.NewDecoder(resp.Body).Decode(&hook)
+		return hook
+	}
+
+	suspended := create(models.WebhookEventCardSuspended)
+	create(models.WebhookEventCardDeleted)
+
+	handler, _ = webhook.NewHandler(suspended.Secret)
+	handler.OnCard(webhook.EventCardSuspended, func(ctx context.Context, event *webhook.CardEvent) error {
+		received = append(received, event.Card.ID)
+		return nil
+	})
+
+	results, err := server.Deliver(context.Background(), models.WebhookEventCardSuspended, models.Card{ID: "0xc4rd1d"})
+	if err != nil {
+		t.Fatalf("Deliver() error = %v", err)
+	}
+
+	if len(results) != 1 || !results[suspended.ID].Delivered {
+		t.Errorf("Deliver() results = %+v, want delivery to %s only", results, suspended.ID)
+	}
+	if len(received) != 1 || received[0] != "0xc4rd1d" {
+		t.Errorf("Deliver() received %v, want [0xc4rd1d]", received)
+	}
+	if server.WebhookSecret(suspended.ID) != suspended.Secret {
+		t.Error("WebhookSecret() did not match the secret returned on create")
+	}
+}
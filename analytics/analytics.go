@@ -0,0 +1,295 @@
+// Package analytics computes install funnels, time-to-install
+// distributions, device breakdowns and daily rollups from event logs and
+// card data
+package analytics
+
+import (
+	"iter"
+	"sort"
+	"time"
+
+	"github.com/Access-Grid/accessgrid-go/models"
+)
+
+// Unknown is the breakdown key used when an event does not say which
+// platform or device type it came from
+const Unknown = "unknown"
+
+// cardProgress tracks how far a single card has moved through the funnel
+type cardProgress struct {
+	templateID    string
+	createdAt     time.Time
+	provisionedAt time.Time
+	linkOpenedAt  time.Time
+	installedAt   time.Time
+}
+
+// dayKey identifies a daily rollup
+type dayKey struct {
+	date       string
+	templateID string
+}
+
+// Analyzer accumulates events and card data and produces a Report. It is
+// not safe for concurrent use.
+type Analyzer struct {
+	location  *time.Location
+	cards     map[string]*cardProgress
+	platforms map[string]*Breakdown
+	devices   map[string]*Breakdown
+	days      map[dayKey]*DailyRollup
+	seen      map[string]bool
+}
+
+// Option allows for customizing the analyzer
+type Option func(*Analyzer)
+
+// WithLocation sets the time zone used to bucket daily rollups. The default
+// is UTC.
+func WithLocation(loc *time.Location) Option {
+	return func(a *Analyzer) {
+		a.location = loc
+	}
+}
+
+// New creates an Analyzer seeded with the given cards. Cards count as
+// provisioned at their CreatedAt time unless a provision event says
+// otherwise.
+func New(cards []models.Card, options ...Option) *Analyzer {
+	a := &Analyzer{
+		location:  time.UTC,
+		cards:     make(map[string]*cardProgress),
+		platforms: make(map[string]*Breakdown),
+		devices:   make(map[string]*Breakdown),
+		days:      make(map[dayKey]*DailyRollup),
+		seen:      make(map[string]bool),
+	}
+
+	// Apply any custom options
+	for _, option := range options {
+		option(a)
+	}
+
+	for _, card := range cards {
+		a.card(card.ID, card.CardTemplateID).createdAt = card.CreatedAt
+	}
+	return a
+}
+
+// Add records events. Events with an ID that has already been added are
+// ignored, so overlapping fetches can be fed in safely.
+func (a *Analyzer) Add(events ...models.Event) {
+	for _, event := range events {
+		if event.ID != "" {
+			if a.seen[event.ID] {
+				continue
+			}
+			a.seen[event.ID] = true
+		}
+		a.add(event)
+	}
+}
+
+// AddSeq records every event from seq, such as the iterators returned by
+// ConsoleService.EventLogIter and AccountEventLogIter. It stops at and
+// returns the first error.
+func (a *Analyzer) AddSeq(seq iter.Seq2[models.Event, error]) error {
+	for event, err := range seq {
+		if err != nil {
+			return err
+		}
+		a.Add(event)
+	}
+	return nil
+}
+
+func (a *Analyzer) add(event models.Event) {
+	a.rollup(event).count(event.Type)
+
+	var progress *cardProgress
+	if event.CardID != "" {
+		progress = a.card(event.CardID, event.TemplateID)
+	}
+
+	switch event.Type {
+	case models.EventTypeProvision:
+		if progress != nil {
+			progress.provisionedAt = earliest(progress.provisionedAt, event.Timestamp)
+		}
+	case models.EventTypeLinkOpened:
+		if progress != nil {
+			progress.linkOpenedAt = earliest(progress.linkOpenedAt, event.Timestamp)
+		}
+	case models.EventTypeInstall:
+		if progress != nil {
+			progress.installedAt = earliest(progress.installedAt, event.Timestamp)
+		}
+		a.breakdown(event, func(b *Breakdown) { b.Installs++ })
+	case models.EventTypeDeviceAdded:
+		a.breakdown(event, func(b *Breakdown) { b.Installs++ })
+	case models.EventTypeUninstall, models.EventTypeDeviceRemoved:
+		a.breakdown(event, func(b *Breakdown) { b.Uninstalls++ })
+	case models.EventTypeTap:
+		a.breakdown(event, func(b *Breakdown) { b.Taps++ })
+	}
+}
+
+// card returns the progress for a card, creating it if needed
+func (a *Analyzer) card(cardID, templateID string) *cardProgress {
+	progress, ok := a.cards[cardID]
+	if !ok {
+		progress = &cardProgress{}
+		a.cards[cardID] = progress
+	}
+	if progress.templateID == "" {
+		progress.templateID = templateID
+	}
+	return progress
+}
+
+// rollup returns the daily rollup an event falls into
+func (a *Analyzer) rollup(event models.Event) *DailyRollup {
+	return rollupFor(a.days, a.day(event.Timestamp), event.TemplateID)
+}
+
+// day formats t as a rollup date in the analyzer's location
+func (a *Analyzer) day(t time.Time) string {
+	return t.In(a.location).Format(time.DateOnly)
+}
+
+func rollupFor(days map[dayKey]*DailyRollup, date, templateID string) *DailyRollup {
+	key := dayKey{date: date, templateID: templateID}
+	day, ok := days[key]
+	if !ok {
+		day = &DailyRollup{Date: date, TemplateID: templateID}
+		days[key] = day
+	}
+	return day
+}
+
+// breakdown applies fn to the platform and device type breakdowns for an
+// event
+func (a *Analyzer) breakdown(event models.Event, fn func(*Breakdown)) {
+	platform, deviceType := Unknown, Unknown
+	switch details := event.ParsedDetails().(type) {
+	case *models.DeviceEventDetails:
+		if details.Platform != "" {
+			platform = string(details.Platform)
+		}
+		if details.DeviceType != "" {
+			deviceType = string(details.DeviceType)
+		}
+	case *models.TapEventDetails:
+		if details.Platform != "" {
+			platform = string(details.Platform)
+		}
+	}
+	if deviceType == Unknown && event.Device != "" {
+		deviceType = event.Device
+	}
+
+	fn(breakdownFor(a.platforms, platform))
+	fn(breakdownFor(a.devices, deviceType))
+}
+
+func breakdownFor(m map[string]*Breakdown, key string) *Breakdown {
+	b, ok := m[key]
+	if !ok {
+		b = &Breakdown{Key: key}
+		m[key] = b
+	}
+	return b
+}
+
+// provisioned returns when the card was provisioned: the time of its
+// provision event or, failing that, its creation
+func (p *cardProgress) provisioned() time.Time {
+	if !p.provisionedAt.IsZero() {
+		return p.provisionedAt
+	}
+	return p.createdAt
+}
+
+// earliest returns the earlier of two times, treating the zero time as
+// unset
+func earliest(current, t time.Time) time.Time {
+	if current.IsZero() || t.Before(current) {
+		return t
+	}
+	return current
+}
+
+// Report computes the analytics for everything added so far
+func (a *Analyzer) Report() *Report {
+	report := &Report{
+		Platforms:   sortedBreakdowns(a.platforms),
+		DeviceTypes: sortedBreakdowns(a.devices),
+	}
+
+	byTemplate := make(map[string]*Funnel)
+	var installTimes []time.Duration
+	for _, progress := range a.cards {
+		// Cards seen only through later events have no provision time and
+		// are left out, matching the daily rollup
+		provisioned := progress.provisioned()
+		if provisioned.IsZero() {
+			continue
+		}
+
+		funnel, ok := byTemplate[progress.templateID]
+		if !ok {
+			funnel = &Funnel{TemplateID: progress.templateID}
+			byTemplate[progress.templateID] = funnel
+		}
+		funnel.add(progress)
+		report.Funnel.add(progress)
+
+		if !progress.installedAt.IsZero() && !progress.installedAt.Before(provisioned) {
+			installTimes = append(installTimes, progress.installedAt.Sub(provisioned))
+		}
+	}
+
+	for _, funnel := range byTemplate {
+		funnel.computeRates()
+		report.TemplateFunnels = append(report.TemplateFunnels, *funnel)
+	}
+	sort.Slice(report.TemplateFunnels, func(i, j int) bool {
+		return report.TemplateFunnels[i].TemplateID < report.TemplateFunnels[j].TemplateID
+	})
+	report.Funnel.computeRates()
+	report.TimeToInstall = newDistribution(installTimes)
+
+	// Provisioning is counted once per card rather than per event
+	days := make(map[dayKey]*DailyRollup, len(a.days))
+	for key, day := range a.days {
+		copied := *day
+		days[key] = &copied
+	}
+	for _, progress := range a.cards {
+		if provisioned := progress.provisioned(); !provisioned.IsZero() {
+			rollupFor(days, a.day(provisioned), progress.templateID).Provisioned++
+		}
+	}
+
+	for _, day := range days {
+		report.Daily = append(report.Daily, *day)
+	}
+	sort.Slice(report.Daily, func(i, j int) bool {
+		if report.Daily[i].Date != report.Daily[j].Date {
+			return report.Daily[i].Date < report.Daily[j].Date
+		}
+		return report.Daily[i].TemplateID < report.Daily[j].TemplateID
+	})
+	return report
+}
+
+func sortedBreakdowns(m map[string]*Breakdown) []Breakdown {
+	breakdowns := make([]Breakdown, 0, len(m))
+	for _, b := range m {
+		breakdowns = append(breakdowns, *b)
+	}
+	sort.Slice(breakdowns, func(i, j int) bool {
+		return breakdowns[i].Key < breakdowns[j].Key
+	})
+	return breakdowns
+}
@@ -0,0 +1,225 @@
+package analytics
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"iter"
+	"testing"
+	"time"
+
+	"github.com/Access-Grid/accessgrid-go/models"
+)
+
+var base = time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+func at(d time.Duration) time.Time {
+	return base.Add(d)
+}
+
+func sampleAnalyzer() *Analyzer {
+	cards := []models.Card{
+		{ID: "0xa", CardTemplateID: "0xt1", CreatedAt: at(0)},
+		{ID: "0xb", CardTemplateID: "0xt1", CreatedAt: at(0)},
+		{ID: "0xc", CardTemplateID: "0xt2", CreatedAt: at(-48 * time.Hour)},
+	}
+
+	a := New(cards)
+	a.Add(
+		models.Event{ID: "e1", Type: models.EventTypeLinkOpened, CardID: "0xa", TemplateID: "0xt1", Timestamp: at(10 * time.Minute)},
+		models.Event{ID: "e2", Type: models.EventTypeInstall, CardID: "0xa", TemplateID: "0xt1", Timestamp: at(30 * time.Minute),
+			Details: `{"platform": "apple", "device_type": "iphone"}`},
+		models.Event{ID: "e3", Type: models.EventTypeDeviceAdded, CardID: "0xa", TemplateID: "0xt1", Timestamp: at(time.Hour),
+			Details: `{"platform": "apple", "device_type": "watch"}`},
+		models.Event{ID: "e4", Type: models.EventTypeLinkOpened, CardID: "0xb", TemplateID: "0xt1", Timestamp: at(2 * time.Hour)},
+		// Installed directly, without opening the link
+		models.Event{ID: "e5", Type: models.EventTypeInstall, CardID: "0xc", TemplateID: "0xt2", Timestamp: at(0),
+			Details: `{"platform": "google"}`, Device: "phone"},
+		models.Event{ID: "e6", Type: models.EventTypeTap, CardID: "0xa", TemplateID: "0xt1", Timestamp: at(25 * time.Hour),
+			Details: `{"platform": "apple", "granted": true}`},
+		// Provisioned through an event rather than card data
+		models.Event{ID: "e7", Type: models.EventTypeProvision, CardID: "0xd", TemplateID: "0xt2", Timestamp: at(26 * time.Hour)},
+	)
+	return a
+}
+
+func TestAnalyzer_Funnel(t *testing.T) {
+	report := sampleAnalyzer().Report()
+
+	want := Funnel{Provisioned: 4, LinkOpened: 3, Installed: 2, OpenRate: 0.75, InstallRate: 0.5}
+	if report.Funnel != want {
+		t.Errorf("Funnel = %+v, want %+v", report.Funnel, want)
+	}
+
+	if len(report.TemplateFunnels) != 2 {
+		t.Fatalf("TemplateFunnels = %+v, want 2 templates", report.TemplateFunnels)
+	}
+	t1 := report.TemplateFunnels[0]
+	if t1.TemplateID != "0xt1" || t1.Provisioned != 2 || t1.LinkOpened != 2 || t1.Installed != 1 {
+		t.Errorf("TemplateFunnels[0] = %+v", t1)
+	}
+}
+
+func TestAnalyzer_TimeToInstall(t *testing.T) {
+	dist := sampleAnalyzer().Report().TimeToInstall
+
+	if dist.Count != 2 || dist.Min != 30*time.Minute || dist.Max != 48*time.Hour {
+		t.Errorf("TimeToInstall = %+v", dist)
+	}
+	counts := map[string]int{}
+	for _, b := range dist.Buckets {
+		counts[b.Label] = b.Count
+	}
+	if counts["1h"] != 1 || counts["7d"] != 1 {
+		t.Errorf("TimeToInstall buckets = %+v", dist.Buckets)
+	}
+}
+
+func TestAnalyzer_Breakdowns(t *testing.T) {
+	report := sampleAnalyzer().Report()
+
+	platforms := map[string]Breakdown{}
+	for _, b := range report.Platforms {
+		platforms[b.Key] = b
+	}
+	if platforms["apple"].Installs != 2 || platforms["apple"].Taps != 1 || platforms["google"].Installs != 1 {
+		t.Errorf("Platforms = %+v", report.Platforms)
+	}
+
+	devices := map[string]Breakdown{}
+	for _, b := range report.DeviceTypes {
+		devices[b.Key] = b
+	}
+	if devices["iphone"].Installs != 1 || devices["watch"].Installs != 1 || devices["phone"].Installs != 1 {
+		t.Errorf("DeviceTypes = %+v", report.DeviceTypes)
+	}
+	if devices[Unknown].Taps != 1 {
+		t.Errorf("DeviceTypes[%q] = %+v, want the tap without a device type", Unknown, devices[Unknown])
+	}
+}
+
+func TestAnalyzer_Daily(t *testing.T) {
+	report := sampleAnalyzer().Report()
+
+	days := map[dayKey]DailyRollup{}
+	for _, d := range report.Daily {
+		days[dayKey{d.Date, d.TemplateID}] = d
+	}
+
+	first := days[dayKey{"2024-03-01", "0xt1"}]
+	if first.Provisioned != 2 || first.LinkOpened != 2 || first.Installed != 1 || first.Other != 1 {
+		t.Errorf("2024-03-01/0xt1 = %+v", first)
+	}
+	if got := days[dayKey{"2024-02-28", "0xt2"}].Provisioned; got != 1 {
+		t.Errorf("2024-02-28/0xt2 provisioned = %d, want 1", got)
+	}
+	if got := days[dayKey{"2024-03-02", "0xt2"}].Provisioned; got != 1 {
+		t.Errorf("2024-03-02/0xt2 provisioned = %d, want 1 from the provision event", got)
+	}
+	if got := days[dayKey{"2024-03-02", "0xt1"}].Taps; got != 1 {
+		t.Errorf("2024-03-02/0xt1 taps = %d, want 1", got)
+	}
+}
+
+func TestAnalyzer_ReportIsRepeatable(t *testing.T) {
+	a := sampleAnalyzer()
+	first, _ := json.Marshal(a.Report())
+	second, _ := json.Marshal(a.Report())
+	if !bytes.Equal(first, second) {
+		t.Error("Report() changed between calls")
+	}
+}
+
+func TestAnalyzer_DeduplicatesEvents(t *testing.T) {
+	a := New(nil)
+	event := models.Event{ID: "e1", Type: models.EventTypeTap, TemplateID: "0xt1", Timestamp: base}
+	a.Add(event, event)
+	a.Add(event)
+
+	if taps := a.Report().Daily[0].Taps; taps != 1 {
+		t.Errorf("Taps = %d, want 1", taps)
+	}
+}
+
+func TestAnalyzer_AddSeq(t *testing.T) {
+	failure := errors.New("boom")
+	seq := iter.Seq2[models.Event, error](func(yield func(models.Event, error) bool) {
+		if !yield(models.Event{ID: "e1", Type: models.EventTypeTap, Timestamp: base}, nil) {
+			return
+		}
+		yield(models.Event{}, failure)
+	})
+
+	a := New(nil)
+	if err := a.AddSeq(seq); !errors.Is(err, failure) {
+		t.Errorf("AddSeq() error = %v, want %v", err, failure)
+	}
+	if len(a.Report().Daily) != 1 {
+		t.Error("AddSeq() did not record events before the error")
+	}
+}
+
+func TestReport_CSV(t *testing.T) {
+	report := sampleAnalyzer().Report()
+
+	tests := []struct {
+		name   string
+		write  func(*bytes.Buffer) error
+		header string
+		rows   int
+	}{
+		{"Funnel", func(b *bytes.Buffer) error { return report.WriteFunnelCSV(b) }, "template_id", 1 + 1 + len(report.TemplateFunnels)},
+		{"Breakdown", func(b *bytes.Buffer) error { return report.WriteBreakdownCSV(b) }, "dimension", 1 + len(report.Platforms) + len(report.DeviceTypes)},
+		{"Daily", func(b *bytes.Buffer) error { return report.WriteDailyCSV(b) }, "date", 1 + len(report.Daily)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.write(&buf); err != nil {
+				t.Fatalf("write error = %v", err)
+			}
+			records, err := csv.NewReader(&buf).ReadAll()
+			if err != nil {
+				t.Fatalf("invalid CSV: %v", err)
+			}
+			if len(records) != tt.rows || records[0][0] != tt.header {
+				t.Errorf("CSV = %v", records)
+			}
+		})
+	}
+}
+
+func TestReport_WriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := sampleAnalyzer().Report().WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+
+	var decoded Report
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("WriteJSON() produced invalid JSON: %v", err)
+	}
+	if decoded.Funnel.Installed != 2 {
+		t.Errorf("decoded Funnel = %+v", decoded.Funnel)
+	}
+}
+
+func TestAnalyzer_FunnelMatchesDaily(t *testing.T) {
+	a := sampleAnalyzer()
+	// Seen only through an install, with no provision time
+	a.Add(models.Event{ID: "e8", Type: models.EventTypeInstall, CardID: "0xe", TemplateID: "0xt2", Timestamp: at(time.Hour)})
+
+	report := a.Report()
+	daily := 0
+	for _, day := range report.Daily {
+		daily += day.Provisioned
+	}
+	if report.Funnel.Provisioned != 4 || daily != report.Funnel.Provisioned {
+		t.Errorf("Funnel.Provisioned = %v, daily sum = %v, want both 4", report.Funnel.Provisioned, daily)
+	}
+	if report.Funnel.Installed != 2 {
+		t.Errorf("Funnel.Installed = %v, want card without provision time excluded", report.Funnel.Installed)
+	}
+}
@@ -0,0 +1,237 @@
+package analytics
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/Access-Grid/accessgrid-go/models"
+)
+
+// Report holds the computed analytics. It can be serialized with WriteJSON
+// or exported table by table as CSV.
+type Report struct {
+	Funnel          Funnel        `json:"funnel"`
+	TemplateFunnels []Funnel      `json:"template_funnels"`
+	TimeToInstall   Distribution  `json:"time_to_install"`
+	Platforms       []Breakdown   `json:"platforms"`
+	DeviceTypes     []Breakdown   `json:"device_types"`
+	Daily           []DailyRollup `json:"daily"`
+}
+
+// Funnel counts cards at each install stage. Stages are cumulative: an
+// installed card also counts as provisioned and as having opened its link,
+// even when it was installed directly without a recorded link open. Only
+// cards with a known provision time, from a provision event or the card's
+// creation date, are counted, so Provisioned equals the sum of
+// DailyRollup.Provisioned.
+type Funnel struct {
+	TemplateID  string `json:"template_id,omitempty"`
+	Provisioned int    `json:"provisioned"`
+	LinkOpened  int    `json:"link_opened"`
+	Installed   int    `json:"installed"`
+	// OpenRate and InstallRate are fractions of Provisioned
+	OpenRate    float64 `json:"open_rate"`
+	InstallRate float64 `json:"install_rate"`
+}
+
+func (f *Funnel) add(p *cardProgress) {
+	installed := !p.installedAt.IsZero()
+	opened := installed || !p.linkOpenedAt.IsZero()
+
+	f.Provisioned++
+	if opened {
+		f.LinkOpened++
+	}
+	if installed {
+		f.Installed++
+	}
+}
+
+func (f *Funnel) computeRates() {
+	if f.Provisioned == 0 {
+		return
+	}
+	f.OpenRate = float64(f.LinkOpened) / float64(f.Provisioned)
+	f.InstallRate = float64(f.Installed) / float64(f.Provisioned)
+}
+
+// Bucket counts the install times of at most UpperBound. The last bucket
+// has no upper bound.
+type Bucket struct {
+	Label      string        `json:"label"`
+	UpperBound time.Duration `json:"upper_bound,omitempty"`
+	Count      int           `json:"count"`
+}
+
+// installBuckets are the histogram buckets of a time-to-install
+// distribution
+var installBuckets = []Bucket{
+	{Label: "1h", UpperBound: time.Hour},
+	{Label: "1d", UpperBound: 24 * time.Hour},
+	{Label: "7d", UpperBound: 7 * 24 * time.Hour},
+	{Label: "30d", UpperBound: 30 * 24 * time.Hour},
+	{Label: "30d+"},
+}
+
+// Distribution summarizes the time between provisioning and first install.
+// Durations are encoded in JSON as nanoseconds.
+type Distribution struct {
+	Count   int           `json:"count"`
+	Min     time.Duration `json:"min"`
+	Median  time.Duration `json:"median"`
+	Mean    time.Duration `json:"mean"`
+	P90     time.Duration `json:"p90"`
+	Max     time.Duration `json:"max"`
+	Buckets []Bucket      `json:"buckets"`
+}
+
+func newDistribution(durations []time.Duration) Distribution {
+	d := Distribution{Count: len(durations), Buckets: slices.Clone(installBuckets)}
+	if len(durations) == 0 {
+		return d
+	}
+
+	slices.Sort(durations)
+	var total time.Duration
+	for _, duration := range durations {
+		total += duration
+		for i := range d.Buckets {
+			if d.Buckets[i].UpperBound == 0 || duration <= d.Buckets[i].UpperBound {
+				d.Buckets[i].Count++
+				break
+			}
+		}
+	}
+
+	d.Min = durations[0]
+	d.Max = durations[len(durations)-1]
+	d.Mean = total / time.Duration(len(durations))
+	d.Median = percentile(durations, 0.5)
+	d.P90 = percentile(durations, 0.9)
+	return d
+}
+
+// percentile returns the nearest-rank percentile of sorted durations
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(p*float64(len(sorted))+0.5) - 1
+	return sorted[max(0, min(rank, len(sorted)-1))]
+}
+
+// Breakdown counts install, uninstall and tap events for one platform or
+// device type. Device added and removed events count as installs and
+// uninstalls.
+type Breakdown struct {
+	Key        string `json:"key"`
+	Installs   int    `json:"installs"`
+	Uninstalls int    `json:"uninstalls"`
+	Taps       int    `json:"taps"`
+}
+
+// DailyRollup counts events for one template on one day. Provisioned counts
+// cards, once each; the other fields count events.
+type DailyRollup struct {
+	Date        string `json:"date"`
+	TemplateID  string `json:"template_id"`
+	Provisioned int    `json:"provisioned"`
+	LinkOpened  int    `json:"link_opened"`
+	Installed   int    `json:"installed"`
+	Uninstalled int    `json:"uninstalled"`
+	Suspended   int    `json:"suspended"`
+	Resumed     int    `json:"resumed"`
+	Deleted     int    `json:"deleted"`
+	Taps        int    `json:"taps"`
+	Other       int    `json:"other"`
+}
+
+func (d *DailyRollup) count(t models.EventType) {
+	switch t {
+	case models.EventTypeProvision:
+		// counted per card by Report
+	case models.EventTypeLinkOpened:
+		d.LinkOpened++
+	case models.EventTypeInstall:
+		d.Installed++
+	case models.EventTypeUninstall:
+		d.Uninstalled++
+	case models.EventTypeSuspend:
+		d.Suspended++
+	case models.EventTypeResume:
+		d.Resumed++
+	case models.EventTypeDelete:
+		d.Deleted++
+	case models.EventTypeTap:
+		d.Taps++
+	default:
+		d.Other++
+	}
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteFunnelCSV writes the overall funnel followed by one row per template.
+// The overall row has an empty template ID.
+func (r *Report) WriteFunnelCSV(w io.Writer) error {
+	rows := [][]string{{"template_id", "provisioned", "link_opened", "installed", "open_rate", "install_rate"}}
+	for _, f := range append([]Funnel{r.Funnel}, r.TemplateFunnels...) {
+		rows = append(rows, []string{
+			f.TemplateID,
+			strconv.Itoa(f.Provisioned),
+			strconv.Itoa(f.LinkOpened),
+			strconv.Itoa(f.Installed),
+			strconv.FormatFloat(f.OpenRate, 'f', 4, 64),
+			strconv.FormatFloat(f.InstallRate, 'f', 4, 64),
+		})
+	}
+	return writeCSV(w, rows)
+}
+
+// WriteBreakdownCSV writes the platform and device type breakdowns, with a
+// dimension column of "platform" or "device_type"
+func (r *Report) WriteBreakdownCSV(w io.Writer) error {
+	rows := [][]string{{"dimension", "key", "installs", "uninstalls", "taps"}}
+	add := func(dimension string, breakdowns []Breakdown) {
+		for _, b := range breakdowns {
+			rows = append(rows, []string{dimension, b.Key, strconv.Itoa(b.Installs), strconv.Itoa(b.Uninstalls), strconv.Itoa(b.Taps)})
+		}
+	}
+	add("platform", r.Platforms)
+	add("device_type", r.DeviceTypes)
+	return writeCSV(w, rows)
+}
+
+// WriteDailyCSV writes the daily rollups, one row per template per day
+func (r *Report) WriteDailyCSV(w io.Writer) error {
+	rows := [][]string{{"date", "template_id", "provisioned", "link_opened", "installed", "uninstalled", "suspended", "resumed", "deleted", "taps", "other"}}
+	for _, d := range r.Daily {
+		rows = append(rows, []string{
+			d.Date, d.TemplateID,
+			strconv.Itoa(d.Provisioned),
+			strconv.Itoa(d.LinkOpened),
+			strconv.Itoa(d.Installed),
+			strconv.Itoa(d.Uninstalled),
+			strconv.Itoa(d.Suspended),
+			strconv.Itoa(d.Resumed),
+			strconv.Itoa(d.Deleted),
+			strconv.Itoa(d.Taps),
+			strconv.Itoa(d.Other),
+		})
+	}
+	return writeCSV(w, rows)
+}
+
+func writeCSV(w io.Writer, rows [][]string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
@@ -37,6 +37,12 @@ func (e *APIError) Error() string {
 	return msg
 }
 
+// IsNotFound reports whether err is, or wraps, an APIError with a 404 status
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
 // Client is the main AccessGrid API client
 type Client struct {
 	AccountID  string
@@ -0,0 +1,40 @@
+package atomicfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state.json")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := WriteFile(path, []byte(fmt.Sprintf(`{"writer": %d}`, i)), 0o644); err != nil {
+				t.Errorf("WriteFile() error = %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	var writer int
+	if _, err := fmt.Sscanf(string(data), `{"writer": %d}`, &writer); err != nil {
+		t.Errorf("file content = %q, want one complete write", data)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("directory has %d entries, want temporary files cleaned up", len(entries))
+	}
+}
@@ -0,0 +1,216 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// EventType identifies the kind of an event log entry
+type EventType string
+
+// Known event log types. Events of other types still decode; their details
+// are exposed as RawEventDetails.
+const (
+	EventTypeProvision     EventType = "provision"
+	EventTypeLinkOpened    EventType = "link_opened"
+	EventTypeInstall       EventType = "install"
+	EventTypeUninstall     EventType = "uninstall"
+	EventTypeUpdate        EventType = "update"
+	EventTypeSuspend       EventType = "suspend"
+	EventTypeResume        EventType = "resume"
+	EventTypeUnlink        EventType = "unlink"
+	EventTypeDelete        EventType = "delete"
+	EventTypeDeviceAdded   EventType = "device_added"
+	EventTypeDeviceRemoved EventType = "device_removed"
+	EventTypeTap           EventType = "tap"
+)
+
+// eventDetailsTypes maps each known event type to a constructor for its
+// details payload
+var eventDetailsTypes = map[EventType]func() EventDetails{
+	EventTypeProvision:     func() EventDetails { return &ProvisionEventDetails{} },
+	EventTypeLinkOpened:    func() EventDetails { return &LinkOpenedEventDetails{} },
+	EventTypeInstall:       func() EventDetails { return &DeviceEventDetails{} },
+	EventTypeUninstall:     func() EventDetails { return &DeviceEventDetails{} },
+	EventTypeDeviceAdded:   func() EventDetails { return &DeviceEventDetails{} },
+	EventTypeDeviceRemoved: func() EventDetails { return &DeviceEventDetails{} },
+	EventTypeUpdate:        func() EventDetails { return &UpdateEventDetails{} },
+	EventTypeSuspend:       func() EventDetails { return &StateEventDetails{} },
+	EventTypeResume:        func() EventDetails { return &StateEventDetails{} },
+	EventTypeUnlink:        func() EventDetails { return &StateEventDetails{} },
+	EventTypeDelete:        func() EventDetails { return &StateEventDetails{} },
+	EventTypeTap:           func() EventDetails { return &TapEventDetails{} },
+}
+
+// Known reports whether t is one of the event types defined by this package
+func (t EventType) Known() bool {
+	_, ok := eventDetailsTypes[t]
+	return ok
+}
+
+// IsDevice reports whether the event records a device being installed,
+// added or removed
+func (t EventType) IsDevice() bool {
+	switch t {
+	case EventTypeInstall, EventTypeUninstall, EventTypeDeviceAdded, EventTypeDeviceRemoved:
+		return true
+	}
+	return false
+}
+
+// IsStateChange reports whether the event records a card lifecycle change
+func (t EventType) IsStateChange() bool {
+	switch t {
+	case EventTypeSuspend, EventTypeResume, EventTypeUnlink, EventTypeDelete:
+		return true
+	}
+	return false
+}
+
+// EventDetails is the structured payload of an event, as returned by
+// Event.ParsedDetails. The concrete type depends on the event type.
+type EventDetails interface {
+	eventDetails()
+}
+
+// ProvisionEventDetails describes a provision event
+type ProvisionEventDetails struct {
+	InstallURL string         `json:"install_url,omitempty"`
+	Platform   DevicePlatform `json:"platform,omitempty"`
+}
+
+// LinkOpenedEventDetails describes an install link being opened
+type LinkOpenedEventDetails struct {
+	Platform  DevicePlatform `json:"platform,omitempty"`
+	UserAgent string         `json:"user_agent,omitempty"`
+	IPAddress string         `json:"ip_address,omitempty"`
+}
+
+// DeviceEventDetails describes install, uninstall, device added and device
+// removed events
+type DeviceEventDetails struct {
+	DeviceID   string         `json:"device_id,omitempty"`
+	Platform   DevicePlatform `json:"platform,omitempty"`
+	DeviceType DeviceType     `json:"device_type,omitempty"`
+	Model      string         `json:"model,omitempty"`
+	OSVersion  string         `json:"os_version,omitempty"`
+}
+
+// StateEventDetails describes suspend, resume, unlink and delete events
+type StateEventDetails struct {
+	PreviousState string `json:"previous_state,omitempty"`
+	State         string `json:"state,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+	Actor         string `json:"actor,omitempty"`
+}
+
+// UpdateEventDetails describes an update event
+type UpdateEventDetails struct {
+	Changes map[string]interface{} `json:"changes,omitempty"`
+	Actor   string                 `json:"actor,omitempty"`
+}
+
+// TapEventDetails describes a reader tap
+type TapEventDetails struct {
+	ReaderID string         `json:"reader_id,omitempty"`
+	Location string         `json:"location,omitempty"`
+	Granted  bool           `json:"granted"`
+	Platform DevicePlatform `json:"platform,omitempty"`
+}
+
+// RawEventDetails holds the details of an event whose type is unknown or
+// whose details could not be decoded into the typed payload. Details that
+// are not JSON are stored as a JSON string.
+type RawEventDetails struct {
+	Type EventType
+	Raw  json.RawMessage
+}
+
+func (*ProvisionEventDetails) eventDetails()  {}
+func (*LinkOpenedEventDetails) eventDetails() {}
+func (*DeviceEventDetails) eventDetails()     {}
+func (*StateEventDetails) eventDetails()      {}
+func (*UpdateEventDetails) eventDetails()     {}
+func (*TapEventDetails) eventDetails()        {}
+func (*RawEventDetails) eventDetails()        {}
+
+// ParsedDetails decodes the event's Details into the payload for its type.
+// Details are decoded on each call; unknown types and undecodable details
+// yield a *RawEventDetails rather than an error.
+func (e Event) ParsedDetails() EventDetails {
+	raw := json.RawMessage(strings.TrimSpace(e.Details))
+	if len(raw) == 0 {
+		raw = json.RawMessage("{}")
+	} else if !json.Valid(raw) {
+		raw, _ = json.Marshal(e.Details)
+	}
+
+	if newDetails, ok := eventDetailsTypes[e.Type]; ok {
+		details := newDetails()
+		if err := json.Unmarshal(raw, details); err == nil {
+			return details
+		}
+	}
+	return &RawEventDetails{Type: e.Type, Raw: raw}
+}
+
+// Is reports whether the event has any of the given types
+func (e Event) Is(types ...EventType) bool {
+	for _, t := range types {
+		if e.Type == t {
+			return true
+		}
+	}
+	return false
+}
+
+// EventDetailsAs returns the event's details as T if they decode to that
+// type, e.g. EventDetailsAs[*TapEventDetails](event)
+func EventDetailsAs[T EventDetails](e Event) (T, bool) {
+	details, ok := e.ParsedDetails().(T)
+	return details, ok
+}
+
+// FilterEvents returns the events matching any of the given types, in their
+// original order
+func FilterEvents(events []Event, types ...EventType) []Event {
+	var filtered []Event
+	for _, event := range events {
+		if event.Is(types...) {
+			filtered = append(filtered, event)
+		}
+	}
+	return filtered
+}
+
+// EventSwitch dispatches events to a handler per event type. Events without
+// a registered handler go to Default if it is set.
+type EventSwitch struct {
+	handlers map[EventType]func(Event, EventDetails)
+	Default  func(Event, EventDetails)
+}
+
+// On registers fn for the given event types, replacing any earlier handler
+func (s *EventSwitch) On(fn func(Event, EventDetails), types ...EventType) *EventSwitch {
+	if s.handlers == nil {
+		s.handlers = make(map[EventType]func(Event, EventDetails))
+	}
+	for _, t := range types {
+		s.handlers[t] = fn
+	}
+	return s
+}
+
+// Dispatch decodes the event's details and calls the matching handler. It
+// reports whether a handler was called.
+func (s *EventSwitch) Dispatch(e Event) bool {
+	fn, ok := s.handlers[e.Type]
+	if !ok {
+		fn = s.Default
+	}
+	if fn == nil {
+		return false
+	}
+	fn(e, e.ParsedDetails())
+	return true
+}
@@ -0,0 +1,150 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEvent_ParsedDetails(t *testing.T) {
+	tests := []struct {
+		name  string
+		event Event
+		check func(t *testing.T, details EventDetails)
+	}{
+		{
+			name:  "Install decodes device details",
+			event: Event{Type: EventTypeInstall, Details: `{"device_id": "dev_1", "platform": "apple", "device_type": "watch"}`},
+			check: func(t *testing.T, details EventDetails) {
+				device, ok := details.(*DeviceEventDetails)
+				if !ok {
+					t.Fatalf("ParsedDetails() = %T, want *DeviceEventDetails", details)
+				}
+				if device.DeviceID != "dev_1" || device.Platform != DevicePlatformApple || device.DeviceType != DeviceTypeWatch {
+					t.Errorf("ParsedDetails() = %+v", device)
+				}
+			},
+		},
+		{
+			name:  "Tap decodes tap details",
+			event: Event{Type: EventTypeTap, Details: `{"reader_id": "rdr_1", "granted": true}`},
+			check: func(t *testing.T, details EventDetails) {
+				tap, ok := details.(*TapEventDetails)
+				if !ok || tap.ReaderID != "rdr_1" || !tap.Granted {
+					t.Errorf("ParsedDetails() = %#v, want granted tap on rdr_1", details)
+				}
+			},
+		},
+		{
+			name:  "Empty details decode to zero payload",
+			event: Event{Type: EventTypeSuspend},
+			check: func(t *testing.T, details EventDetails) {
+				if _, ok := details.(*StateEventDetails); !ok {
+					t.Errorf("ParsedDetails() = %T, want *StateEventDetails", details)
+				}
+			},
+		},
+		{
+			name:  "Unknown type falls back to raw JSON",
+			event: Event{Type: "badge_printed", Details: `{"printer": "lobby"}`},
+			check: func(t *testing.T, details EventDetails) {
+				raw, ok := details.(*RawEventDetails)
+				if !ok {
+					t.Fatalf("ParsedDetails() = %T, want *RawEventDetails", details)
+				}
+				if raw.Type != "badge_printed" || string(raw.Raw) != `{"printer": "lobby"}` {
+					t.Errorf("ParsedDetails() = %+v", raw)
+				}
+			},
+		},
+		{
+			name:  "Mismatched payload falls back to raw JSON",
+			event: Event{Type: EventTypeTap, Details: `["unexpected"]`},
+			check: func(t *testing.T, details EventDetails) {
+				if _, ok := details.(*RawEventDetails); !ok {
+					t.Errorf("ParsedDetails() = %T, want *RawEventDetails", details)
+				}
+			},
+		},
+		{
+			name:  "Plain text details are kept as a JSON string",
+			event: Event{Type: EventTypeResume, Details: "resumed by admin"},
+			check: func(t *testing.T, details EventDetails) {
+				raw, ok := details.(*RawEventDetails)
+				if !ok {
+					t.Fatalf("ParsedDetails() = %T, want *RawEventDetails", details)
+				}
+				var text string
+				if err := json.Unmarshal(raw.Raw, &text); err != nil || text != "resumed by admin" {
+					t.Errorf("ParsedDetails() raw = %s", raw.Raw)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.check(t, tt.event.ParsedDetails())
+		})
+	}
+}
+
+func TestEvent_UnknownTypeDecodes(t *testing.T) {
+	var event Event
+	if err := json.Unmarshal([]byte(`{"id": "evt_1", "type": "badge_printed", "details": "{}"}`), &event); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if event.Type != "badge_printed" || event.Type.Known() {
+		t.Errorf("Type = %q, Known() = %v", event.Type, event.Type.Known())
+	}
+}
+
+func TestEventDetailsAs(t *testing.T) {
+	event := Event{Type: EventTypeTap, Details: `{"reader_id": "rdr_1"}`}
+
+	if tap, ok := EventDetailsAs[*TapEventDetails](event); !ok || tap.ReaderID != "rdr_1" {
+		t.Errorf("EventDetailsAs[*TapEventDetails]() = %+v, %v", tap, ok)
+	}
+	if _, ok := EventDetailsAs[*DeviceEventDetails](event); ok {
+		t.Error("EventDetailsAs[*DeviceEventDetails]() ok = true for a tap event")
+	}
+}
+
+func TestFilterEvents(t *testing.T) {
+	events := []Event{
+		{ID: "evt_1", Type: EventTypeInstall},
+		{ID: "evt_2", Type: EventTypeTap},
+		{ID: "evt_3", Type: EventTypeUninstall},
+	}
+
+	filtered := FilterEvents(events, EventTypeInstall, EventTypeUninstall)
+	if len(filtered) != 2 || filtered[0].ID != "evt_1" || filtered[1].ID != "evt_3" {
+		t.Errorf("FilterEvents() = %+v", filtered)
+	}
+}
+
+func TestEventSwitch_Dispatch(t *testing.T) {
+	var devices, others []string
+	var sw EventSwitch
+	sw.On(func(e Event, details EventDetails) {
+		devices = append(devices, details.(*DeviceEventDetails).DeviceID)
+	}, EventTypeInstall, EventTypeDeviceAdded)
+	sw.Default = func(e Event, details EventDetails) {
+		others = append(others, e.ID)
+	}
+
+	sw.Dispatch(Event{ID: "evt_1", Type: EventTypeInstall, Details: `{"device_id": "dev_1"}`})
+	sw.Dispatch(Event{ID: "evt_2", Type: EventTypeDeviceAdded, Details: `{"device_id": "dev_2"}`})
+	sw.Dispatch(Event{ID: "evt_3", Type: "badge_printed"})
+
+	if len(devices) != 2 || devices[1] != "dev_2" {
+		t.Errorf("device handler saw %v", devices)
+	}
+	if len(others) != 1 || others[0] != "evt_3" {
+		t.Errorf("default handler saw %v", others)
+	}
+
+	var empty EventSwitch
+	if empty.Dispatch(Event{Type: EventTypeTap}) {
+		t.Error("Dispatch() = true with no handlers")
+	}
+}
@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // Union is an interface representing the base type for access pass responses.
 // Both Card and UnifiedAccessPass implement this interface.
@@ -8,44 +12,84 @@ type Union interface {
 	GetID() string
 	GetURL() string
 	GetState() string
+	GetCards() []Card
 	isUnion()
 }
 
+// DevicePlatform identifies the wallet platform a device belongs to
+type DevicePlatform string
+
+// Known device platforms
+const (
+	DevicePlatformApple   DevicePlatform = "apple"
+	DevicePlatformGoogle  DevicePlatform = "google"
+	DevicePlatformAndroid DevicePlatform = "android"
+)
+
+// DeviceType identifies the kind of hardware a pass is installed on
+type DeviceType string
+
+// Known device types
+const (
+	DeviceTypeIPhone DeviceType = "iphone"
+	DeviceTypeWatch  DeviceType = "watch"
+	DeviceTypePhone  DeviceType = "phone"
+	DeviceTypeTablet DeviceType = "tablet"
+)
+
+// DeviceStatus represents the lifecycle state of a device
+type DeviceStatus string
+
+// Known device statuses
+const (
+	DeviceStatusActive    DeviceStatus = "active"
+	DeviceStatusSuspended DeviceStatus = "suspended"
+	DeviceStatusUnlinked  DeviceStatus = "unlinked"
+)
+
 // Device represents a device associated with an access pass
 type Device struct {
-	ID         string    `json:"id"`
-	Platform   string    `json:"platform"`
-	DeviceType string    `json:"device_type"`
-	Status     string    `json:"status"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID         string         `json:"id"`
+	Platform   DevicePlatform `json:"platform"`
+	DeviceType DeviceType     `json:"device_type"`
+	Status     DeviceStatus   `json:"status"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
 }
 
 // Card represents an NFC key or access pass
 type Card struct {
-	ID                    string                 `json:"id"`
-	CardTemplateID        string                 `json:"card_template_id"`
-	EmployeeID            string                 `json:"employee_id"`
-	CardNumber            string                 `json:"card_number"`
-	SiteCode              string                 `json:"site_code,omitempty"`
-	FullName              string                 `json:"full_name"`
-	Email                 string                 `json:"email"`
-	PhoneNumber           string                 `json:"phone_number"`
-	Classification        string                 `json:"classification"`
-	StartDate             time.Time              `json:"start_date"`
-	ExpirationDate        time.Time              `json:"expiration_date"`
-	EmployeePhoto         string                 `json:"employee_photo"`
-	State                 string                 `json:"state"`
-	URL                   string                 `json:"install_url"`
-	Details               interface{}            `json:"details,omitempty"`
-	FileData              string                 `json:"file_data,omitempty"`
-	DirectInstallURL      string                 `json:"direct_install_url,omitempty"`
-	Devices               []Device               `json:"devices,omitempty"`
-	Metadata              map[string]interface{} `json:"metadata,omitempty"`
-	CreatedAt             time.Time              `json:"created_at"`
-	UpdatedAt             time.Time              `json:"updated_at"`
+	ID               string                 `json:"id"`
+	CardTemplateID   string                 `json:"card_template_id"`
+	EmployeeID       string                 `json:"employee_id"`
+	CardNumber       string                 `json:"card_number"`
+	SiteCode         string                 `json:"site_code,omitempty"`
+	FullName         string                 `json:"full_name"`
+	Email            string                 `json:"email"`
+	PhoneNumber      string                 `json:"phone_number"`
+	Classification   string                 `json:"classification"`
+	StartDate        time.Time              `json:"start_date"`
+	ExpirationDate   time.Time              `json:"expiration_date"`
+	EmployeePhoto    string                 `json:"employee_photo"`
+	State            string                 `json:"state"`
+	URL              string                 `json:"install_url"`
+	Platform         DevicePlatform         `json:"platform,omitempty"`
+	Details          interface{}            `json:"details,omitempty"`
+	FileData         string                 `json:"file_data,omitempty"`
+	DirectInstallURL string                 `json:"direct_install_url,omitempty"`
+	Devices          []Device               `json:"devices,omitempty"`
+	Metadata         map[string]interface{} `json:"metadata,omitempty"`
+	CreatedAt        time.Time              `json:"created_at"`
+	UpdatedAt        time.Time              `json:"updated_at"`
 }
 
+// Card states reported by the API
+const (
+	CardStateActive    = "active"
+	CardStateSuspended = "suspended"
+	CardStateDeleted   = "deleted"
+)
+
 // CardProvisionResponse represents the response from provisioning a card
 type CardProvisionResponse struct {
 	ID               string    `json:"id"`
@@ -70,18 +114,23 @@ type CardProvisionResponse struct {
 
 // ProvisionParams defines parameters for provisioning a new card
 type ProvisionParams struct {
-	CardTemplateID string    `json:"card_template_id"`
-	EmployeeID     string    `json:"employee_id"`
-	CardNumber     string    `json:"card_number"`
-	SiteCode       string    `json:"site_code,omitempty"`
-	FullName       string    `json:"full_name"`
-	Email          string    `json:"email"`
-	PhoneNumber    string    `json:"phone_number"`
-	Classification string    `json:"classification"`
-	Title          string    `json:"title,omitempty"`
-	StartDate      time.Time `json:"start_date"`
-	ExpirationDate time.Time `json:"expiration_date"`
-	EmployeePhoto  string    `json:"employee_photo"`
+	CardTemplateID string `json:"card_template_id,omitempty"`
+	// CardTemplatePairID provisions against a template pair instead of a
+	// single template, yielding a UnifiedAccessPass. Set one of
+	// CardTemplateID or CardTemplatePairID.
+	CardTemplatePairID string                 `json:"card_template_pair_id,omitempty"`
+	EmployeeID         string                 `json:"employee_id"`
+	CardNumber         string                 `json:"card_number"`
+	SiteCode           string                 `json:"site_code,omitempty"`
+	FullName           string                 `json:"full_name"`
+	Email              string                 `json:"email"`
+	PhoneNumber        string                 `json:"phone_number"`
+	Classification     string                 `json:"classification"`
+	Title              string                 `json:"title,omitempty"`
+	StartDate          time.Time              `json:"start_date"`
+	ExpirationDate     time.Time              `json:"expiration_date"`
+	EmployeePhoto      string                 `json:"employee_photo"`
+	Metadata           map[string]interface{} `json:"metadata,omitempty"`
 }
 
 // UpdateParams defines parameters for updating an existing card
@@ -107,17 +156,17 @@ type ListKeysParams struct {
 
 // Template represents a card template
 type Template struct {
-	ID          string         `json:"id"`
-	Name        string         `json:"name"`
-	Platform    string         `json:"platform"`
-	UseCase     string         `json:"use_case"`
-	Protocol    string         `json:"protocol"`
-	WatchCount  int            `json:"watch_count"`
-	IPhoneCount int            `json:"iphone_count"`
-	Design      TemplateDesign `json:"design"`
-	SupportInfo SupportInfo    `json:"support_info"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
+	ID          string           `json:"id"`
+	Name        string           `json:"name"`
+	Platform    TemplatePlatform `json:"platform"`
+	UseCase     TemplateUseCase  `json:"use_case"`
+	Protocol    TemplateProtocol `json:"protocol"`
+	WatchCount  int              `json:"watch_count"`
+	IPhoneCount int              `json:"iphone_count"`
+	Design      TemplateDesign   `json:"design"`
+	SupportInfo SupportInfo      `json:"support_info"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
 }
 
 // TemplateDesign represents the design elements of a card template
@@ -141,14 +190,14 @@ type SupportInfo struct {
 
 // CreateTemplateParams defines parameters for creating a new template
 type CreateTemplateParams struct {
-	Name        string         `json:"name"`
-	Platform    string         `json:"platform"`
-	UseCase     string         `json:"use_case"`
-	Protocol    string         `json:"protocol"`
-	WatchCount  int            `json:"watch_count"`
-	IPhoneCount int            `json:"iphone_count"`
-	Design      TemplateDesign `json:"design"`
-	SupportInfo SupportInfo    `json:"support_info"`
+	Name        string           `json:"name"`
+	Platform    TemplatePlatform `json:"platform"`
+	UseCase     TemplateUseCase  `json:"use_case"`
+	Protocol    TemplateProtocol `json:"protocol"`
+	WatchCount  int              `json:"watch_count"`
+	IPhoneCount int              `json:"iphone_count"`
+	Design      TemplateDesign   `json:"design"`
+	SupportInfo SupportInfo      `json:"support_info"`
 }
 
 // UpdateTemplateParams defines parameters for updating an existing template
@@ -161,18 +210,97 @@ type UpdateTemplateParams struct {
 	SupportInfo    *SupportInfo    `json:"support_info,omitempty"`
 }
 
-// EventLogFilters defines parameters for filtering event logs
+// TemplatePair links an Apple and a Google template. Provisioning against a
+// pair issues a linked card on each platform as a UnifiedAccessPass.
+type TemplatePair struct {
+	ID                   string    `json:"id"`
+	Name                 string    `json:"name"`
+	AppleCardTemplateID  string    `json:"apple_card_template_id"`
+	GoogleCardTemplateID string    `json:"google_card_template_id"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+// CreateTemplatePairParams defines parameters for creating a template pair
+type CreateTemplatePairParams struct {
+	Name                 string `json:"name"`
+	AppleCardTemplateID  string `json:"apple_card_template_id"`
+	GoogleCardTemplateID string `json:"google_card_template_id"`
+}
+
+// TemplateVersion is a snapshot of a template taken before it was updated
+type TemplateVersion struct {
+	TemplateID string    `json:"template_id"`
+	Version    int       `json:"version"`
+	SavedAt    time.Time `json:"saved_at"`
+	Template   Template  `json:"template"`
+}
+
+// TemplateChange is a single field that differs between two template
+// versions
+type TemplateChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// CloneTemplateParams defines overrides applied when cloning a template.
+// Empty fields keep the source template's values.
+type CloneTemplateParams struct {
+	Name        string           `json:"name,omitempty"`
+	Platform    TemplatePlatform `json:"platform,omitempty"`
+	UseCase     TemplateUseCase  `json:"use_case,omitempty"`
+	Protocol    TemplateProtocol `json:"protocol,omitempty"`
+	WatchCount  *int             `json:"watch_count,omitempty"`
+	IPhoneCount *int             `json:"iphone_count,omitempty"`
+}
+
+// SkippedField records a template field that could not be copied
+type SkippedField struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+// CloneTemplateResult describes a cloned template
+type CloneTemplateResult struct {
+	SourceID string         `json:"source_id"`
+	Template *Template      `json:"template"`
+	Skipped  []SkippedField `json:"skipped,omitempty"`
+}
+
+// SortOrder controls the order in which event logs are returned
+type SortOrder string
+
+// Sort orders for event logs
+const (
+	SortAscending  SortOrder = "asc"
+	SortDescending SortOrder = "desc"
+)
+
+// EventLogFilters defines parameters for filtering event logs. Device and
+// EventType are combined with Devices and EventTypes; an event matches if it
+// has any of the listed values.
 type EventLogFilters struct {
-	Device    string     `json:"device,omitempty"`
-	StartDate *time.Time `json:"start_date,omitempty"`
-	EndDate   *time.Time `json:"end_date,omitempty"`
-	EventType string     `json:"event_type,omitempty"`
+	Device     string      `json:"device,omitempty"`
+	Devices    []string    `json:"devices,omitempty"`
+	StartDate  *time.Time  `json:"start_date,omitempty"`
+	EndDate    *time.Time  `json:"end_date,omitempty"`
+	EventType  EventType   `json:"event_type,omitempty"`
+	EventTypes []EventType `json:"event_types,omitempty"`
+	CardID     string      `json:"card_id,omitempty"`
+	UserID     string      `json:"user_id,omitempty"`
+	Sort       SortOrder   `json:"sort,omitempty"`
+	// Page and PerPage request a single page of results. They are managed
+	// by the iterators and only need to be set when calling EventLog
+	// directly.
+	Page    int `json:"page,omitempty"`
+	PerPage int `json:"per_page,omitempty"`
 }
 
 // Event represents an event in the event log
 type Event struct {
 	ID         string    `json:"id"`
-	Type       string    `json:"type"`
+	Type       EventType `json:"type"`
 	UserID     string    `json:"user_id"`
 	CardID     string    `json:"card_id"`
 	TemplateID string    `json:"template_id"`
@@ -189,12 +317,320 @@ type UnifiedAccessPass struct {
 	Details []Card `json:"details"`
 }
 
+// StateMixed is reported for a unified access pass whose child cards do not
+// all share the same state
+const StateMixed = "mixed"
+
 func (u *UnifiedAccessPass) GetID() string    { return u.ID }
 func (u *UnifiedAccessPass) GetURL() string   { return u.URL }
 func (u *UnifiedAccessPass) GetState() string { return u.State }
+func (u *UnifiedAccessPass) GetCards() []Card { return u.Details }
 func (u *UnifiedAccessPass) isUnion()         {}
 
+// CardForPlatform returns the child card issued for the given platform
+func (u *UnifiedAccessPass) CardForPlatform(platform DevicePlatform) (*Card, bool) {
+	for i := range u.Details {
+		if u.Details[i].Platform == platform {
+			return &u.Details[i], true
+		}
+	}
+	return nil, false
+}
+
+// AppleCard returns the child card issued for Apple Wallet
+func (u *UnifiedAccessPass) AppleCard() (*Card, bool) {
+	return u.CardForPlatform(DevicePlatformApple)
+}
+
+// GoogleCard returns the child card issued for Google Wallet, which may be
+// labelled either google or android
+func (u *UnifiedAccessPass) GoogleCard() (*Card, bool) {
+	if card, ok := u.CardForPlatform(DevicePlatformGoogle); ok {
+		return card, true
+	}
+	return u.CardForPlatform(DevicePlatformAndroid)
+}
+
+// ChildStates returns the state of each child card keyed by card ID
+func (u *UnifiedAccessPass) ChildStates() map[string]string {
+	states := make(map[string]string, len(u.Details))
+	for _, card := range u.Details {
+		states[card.ID] = card.State
+	}
+	return states
+}
+
+// AggregateState returns the state shared by every child card, or StateMixed
+// if the children disagree. It returns an empty string when there are no
+// children.
+func (u *UnifiedAccessPass) AggregateState() string {
+	var state string
+	for i, card := range u.Details {
+		if i == 0 {
+			state = card.State
+		} else if card.State != state {
+			return StateMixed
+		}
+	}
+	return state
+}
+
 func (c *Card) GetID() string    { return c.ID }
 func (c *Card) GetURL() string   { return c.URL }
 func (c *Card) GetState() string { return c.State }
+func (c *Card) GetCards() []Card { return []Card{*c} }
 func (c *Card) isUnion()         {}
+
+// CardLookup holds the cards matching a lookup, grouped by template
+type CardLookup struct {
+	Field      string
+	Value      string
+	Cards      []Card
+	ByTemplate map[string][]Card
+}
+
+// NewCardLookup groups cards matching field=value by template
+func NewCardLookup(field, value string, cards []Card) *CardLookup {
+	byTemplate := make(map[string][]Card)
+	for _, card := range cards {
+		byTemplate[card.CardTemplateID] = append(byTemplate[card.CardTemplateID], card)
+	}
+	return &CardLookup{
+		Field:      field,
+		Value:      value,
+		Cards:      cards,
+		ByTemplate: byTemplate,
+	}
+}
+
+// Ambiguous reports whether more than one card matched
+func (l *CardLookup) Ambiguous() bool {
+	return len(l.Cards) > 1
+}
+
+// Single returns the only matching card. It returns a *LookupError if no
+// card or more than one card matched, rather than picking one.
+func (l *CardLookup) Single() (*Card, error) {
+	if len(l.Cards) != 1 {
+		ids := make([]string, len(l.Cards))
+		for i, card := range l.Cards {
+			ids[i] = card.ID
+		}
+		return nil, &LookupError{Field: l.Field, Value: l.Value, CardIDs: ids}
+	}
+	return &l.Cards[0], nil
+}
+
+// LookupError is returned when a lookup that expects exactly one card finds
+// none or several
+type LookupError struct {
+	Field   string
+	Value   string
+	CardIDs []string
+}
+
+// Error implements the error interface
+func (e *LookupError) Error() string {
+	if len(e.CardIDs) == 0 {
+		return fmt.Sprintf("no card found with %s %q", e.Field, e.Value)
+	}
+	return fmt.Sprintf("ambiguous match: %d cards found with %s %q (%s)", len(e.CardIDs), e.Field, e.Value, strings.Join(e.CardIDs, ", "))
+}
+
+// ReissueParams defines overrides applied when reissuing a card. Zero values
+// keep the attributes of the card being replaced.
+type ReissueParams struct {
+	// CardTemplateID provisions the replacement on a different template
+	CardTemplateID string
+	CardNumber     string
+	SiteCode       string
+	StartDate      *time.Time
+	ExpirationDate *time.Time
+	// SuspendOld suspends the replaced card instead of deleting it
+	SuspendOld bool
+}
+
+// ReissueStep records the outcome of a single step of a reissue
+type ReissueStep struct {
+	Name   string `json:"name"`
+	CardID string `json:"card_id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ReissueReport describes every step taken while reissuing a card
+type ReissueReport struct {
+	OldCardID  string        `json:"old_card_id"`
+	NewCard    Union         `json:"-"`
+	Steps      []ReissueStep `json:"steps"`
+	RolledBack bool          `json:"rolled_back"`
+}
+
+// OffboardParams defines how an employee's cards are revoked
+type OffboardParams struct {
+	// UnlinkDevices unlinks every device from each card after suspending it
+	UnlinkDevices bool
+	// Delete removes each card once it has been suspended for GracePeriod.
+	// Cards still inside the grace period are reported as pending; run
+	// Offboard again after the period to delete them.
+	Delete      bool
+	GracePeriod time.Duration
+	// Previous is the report from an earlier Offboard run for the same
+	// employee. Its suspend times are used for cards whose suspension does
+	// not appear in the event log.
+	Previous *OffboardReport
+}
+
+// Offboard action outcomes
+const (
+	OffboardStatusDone    = "done"
+	OffboardStatusSkipped = "skipped"
+	OffboardStatusPending = "pending"
+	OffboardStatusFailed  = "failed"
+)
+
+// OffboardAction records a single action taken on one card while offboarding
+type OffboardAction struct {
+	CardID     string    `json:"card_id"`
+	TemplateID string    `json:"template_id"`
+	Action     string    `json:"action"`
+	Status     string    `json:"status"`
+	Detail     string    `json:"detail,omitempty"`
+	At         time.Time `json:"at"`
+}
+
+// OffboardReport is an audit trail of every action taken while offboarding
+// an employee
+type OffboardReport struct {
+	EmployeeID  string           `json:"employee_id"`
+	StartedAt   time.Time        `json:"started_at"`
+	CompletedAt time.Time        `json:"completed_at"`
+	Actions     []OffboardAction `json:"actions"`
+	// Complete is true when every requested action has been carried out and
+	// nothing is pending or failed
+	Complete bool `json:"complete"`
+}
+
+// Template deletion actions recorded in TemplateDeletionAction
+const (
+	TemplateDeletionMigrate = "migrate"
+	TemplateDeletionSuspend = "suspend"
+)
+
+// DeleteTemplateParams controls how SafeDeleteTemplate treats the cards
+// still issued from a template
+type DeleteTemplateParams struct {
+	// Force deletes the template even if active cards remain
+	Force bool
+	// MigrateToTemplateID reissues each active card on this template before
+	// deleting. Suspended cards are not migrated, so access is not
+	// re-enabled for them.
+	MigrateToTemplateID string
+	// SuspendActive suspends each active card before deleting. It is
+	// ignored when MigrateToTemplateID is set.
+	SuspendActive bool
+	// DryRun reports what would be affected without changing anything
+	DryRun bool
+}
+
+// TemplateDeletionAction records what was done, or would be done, to one
+// card before its template was deleted
+type TemplateDeletionAction struct {
+	CardID    string `json:"card_id"`
+	Action    string `json:"action"`
+	NewCardID string `json:"new_card_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// TemplateDeletionReport describes the cards affected by deleting a
+// template
+type TemplateDeletionReport struct {
+	TemplateID   string `json:"template_id"`
+	TemplateName string `json:"template_name"`
+	// CardCounts is the number of cards in each state before any action
+	// was taken
+	CardCounts map[string]int           `json:"card_counts"`
+	Actions    []TemplateDeletionAction `json:"actions,omitempty"`
+	// RemainingActive is the number of active cards left once migrations
+	// and suspensions have been applied
+	RemainingActive int  `json:"remaining_active"`
+	DryRun          bool `json:"dry_run"`
+	Deleted         bool `json:"deleted"`
+}
+
+// EventCheckpoint records how far an event watch has read for one template
+type EventCheckpoint struct {
+	// Timestamp is the time of the latest event delivered
+	Timestamp time.Time `json:"timestamp"`
+	// SeenIDs are the IDs of the events delivered at Timestamp, so events
+	// sharing that timestamp are not delivered again on resume
+	SeenIDs []string `json:"seen_ids"`
+}
+
+// WebhookEventType identifies the kind of webhook event
+type WebhookEventType string
+
+// Known webhook event types
+const (
+	WebhookEventCardCreated     WebhookEventType = "card.created"
+	WebhookEventCardUpdated     WebhookEventType = "card.updated"
+	WebhookEventCardInstalled   WebhookEventType = "card.installed"
+	WebhookEventCardUninstalled WebhookEventType = "card.uninstalled"
+	WebhookEventCardSuspended   WebhookEventType = "card.suspended"
+	WebhookEventCardResumed     WebhookEventType = "card.resumed"
+	WebhookEventCardUnlinked    WebhookEventType = "card.unlinked"
+	WebhookEventCardDeleted     WebhookEventType = "card.deleted"
+
+	WebhookEventTemplateCreated WebhookEventType = "template.created"
+	WebhookEventTemplateUpdated WebhookEventType = "template.updated"
+	WebhookEventTemplateDeleted WebhookEventType = "template.deleted"
+
+	// WebhookEventTest is sent when an endpoint is tested
+	WebhookEventTest WebhookEventType = "webhook.test"
+)
+
+// IsCard reports whether the event carries a card
+func (t WebhookEventType) IsCard() bool {
+	return strings.HasPrefix(string(t), "card.")
+}
+
+// IsTemplate reports whether the event carries a template
+func (t WebhookEventType) IsTemplate() bool {
+	return strings.HasPrefix(string(t), "template.")
+}
+
+// Webhook represents a webhook endpoint subscription
+type Webhook struct {
+	ID          string             `json:"id"`
+	URL         string             `json:"url"`
+	Description string             `json:"description,omitempty"`
+	EventTypes  []WebhookEventType `json:"event_types"`
+	Enabled     bool               `json:"enabled"`
+	// Secret is the endpoint's signing secret. It is only returned when the
+	// endpoint is created or its secret is rotated.
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CreateWebhookParams defines parameters for creating a webhook endpoint
+type CreateWebhookParams struct {
+	URL         string             `json:"url"`
+	Description string             `json:"description,omitempty"`
+	EventTypes  []WebhookEventType `json:"event_types"`
+}
+
+// UpdateWebhookParams defines parameters for updating a webhook endpoint
+type UpdateWebhookParams struct {
+	WebhookID   string             `json:"-"`
+	URL         string             `json:"url,omitempty"`
+	Description string             `json:"description,omitempty"`
+	EventTypes  []WebhookEventType `json:"event_types,omitempty"`
+	Enabled     *bool              `json:"enabled,omitempty"`
+}
+
+// WebhookTestResult is the outcome of sending a test event to an endpoint
+type WebhookTestResult struct {
+	Delivered  bool   `json:"delivered"`
+	StatusCode int    `json:"status_code"`
+	Error      string `json:"error,omitempty"`
+}
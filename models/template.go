@@ -0,0 +1,278 @@
+package models
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// TemplatePlatform is the wallet a template issues passes to
+type TemplatePlatform string
+
+// Template platforms
+const (
+	TemplatePlatformApple  TemplatePlatform = "apple"
+	TemplatePlatformGoogle TemplatePlatform = "google"
+	// TemplatePlatformAndroid is accepted as an alias of
+	// TemplatePlatformGoogle
+	TemplatePlatformAndroid TemplatePlatform = "android"
+)
+
+// TemplateUseCase describes what passes issued from a template are for
+type TemplateUseCase string
+
+// Template use cases
+const (
+	UseCaseEmployeeBadge TemplateUseCase = "employee_badge"
+	UseCaseStudentID     TemplateUseCase = "student_id"
+	UseCaseHotel         TemplateUseCase = "hotel"
+	UseCaseResidential   TemplateUseCase = "residential"
+)
+
+// TemplateProtocol is the NFC protocol passes from a template use
+type TemplateProtocol string
+
+// Template protocols
+const (
+	ProtocolDESFire  TemplateProtocol = "desfire"
+	ProtocolSEOS     TemplateProtocol = "seos"
+	ProtocolSmartTap TemplateProtocol = "smart_tap"
+)
+
+// MaxDeviceCount is the largest watch or iPhone count a template accepts
+const MaxDeviceCount = 10
+
+// platformSupport describes what a platform allows
+type platformSupport struct {
+	protocols []TemplateProtocol
+	// deviceCounts reports whether WatchCount and IPhoneCount apply
+	deviceCounts bool
+}
+
+// compatibility is the matrix of protocols and device counts supported by
+// each platform
+var compatibility = map[TemplatePlatform]platformSupport{
+	TemplatePlatformApple:  {protocols: []TemplateProtocol{ProtocolDESFire, ProtocolSEOS}, deviceCounts: true},
+	TemplatePlatformGoogle: {protocols: []TemplateProtocol{ProtocolDESFire, ProtocolSmartTap}},
+}
+
+var useCases = []TemplateUseCase{UseCaseEmployeeBadge, UseCaseStudentID, UseCaseHotel, UseCaseResidential}
+
+// Canonical returns the platform p stands for, resolving aliases such as
+// TemplatePlatformAndroid
+func (p TemplatePlatform) Canonical() TemplatePlatform {
+	if p == TemplatePlatformAndroid {
+		return TemplatePlatformGoogle
+	}
+	return p
+}
+
+// Valid reports whether p is a known platform or alias
+func (p TemplatePlatform) Valid() bool {
+	_, ok := compatibility[p.Canonical()]
+	return ok
+}
+
+// Protocols returns the protocols supported on p
+func (p TemplatePlatform) Protocols() []TemplateProtocol {
+	return slices.Clone(compatibility[p.Canonical()].protocols)
+}
+
+// Supports reports whether p supports protocol
+func (p TemplatePlatform) Supports(protocol TemplateProtocol) bool {
+	return slices.Contains(compatibility[p.Canonical()].protocols, protocol)
+}
+
+// SupportsDeviceCounts reports whether WatchCount and IPhoneCount apply to
+// templates on p
+func (p TemplatePlatform) SupportsDeviceCounts() bool {
+	return compatibility[p.Canonical()].deviceCounts
+}
+
+// Valid reports whether u is a known use case
+func (u TemplateUseCase) Valid() bool {
+	return slices.Contains(useCases, u)
+}
+
+// Valid reports whether p is a known protocol
+func (p TemplateProtocol) Valid() bool {
+	for _, support := range compatibility {
+		if slices.Contains(support.protocols, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// FieldError is a validation failure for one field
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists every invalid field found by a Validate method or
+// by the templatedesign pipeline
+type ValidationError struct {
+	Errors []FieldError `json:"errors"`
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		msgs = append(msgs, fmt.Sprintf("%s %s", fe.Field, fe.Message))
+	}
+	return "invalid parameters: " + strings.Join(msgs, "; ")
+}
+
+// HasField reports whether field has an error
+func (e *ValidationError) HasField(field string) bool {
+	return slices.ContainsFunc(e.Errors, func(fe FieldError) bool { return fe.Field == field })
+}
+
+// Add records an error for field
+func (e *ValidationError) Add(field, message string) {
+	e.Errors = append(e.Errors, FieldError{Field: field, Message: message})
+}
+
+func (e *ValidationError) addf(field, format string, args ...interface{}) {
+	e.Add(field, fmt.Sprintf(format, args...))
+}
+
+// Err returns e if it holds any errors and nil otherwise
+func (e *ValidationError) Err() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Validate checks the params before they are sent to the API: known
+// platform, use case and protocol, a protocol and device counts the
+// platform supports, and complete, well-formed support info
+func (p CreateTemplateParams) Validate() error {
+	verr := &ValidationError{}
+	if strings.TrimSpace(p.Name) == "" {
+		verr.addf("name", "is required")
+	}
+
+	if !p.Platform.Valid() {
+		verr.addf("platform", "must be one of apple, google or android; got %q", p.Platform)
+	}
+	if !p.UseCase.Valid() {
+		verr.addf("use_case", "is not a known use case: %q", p.UseCase)
+	}
+	switch {
+	case !p.Protocol.Valid():
+		verr.addf("protocol", "is not a known protocol: %q", p.Protocol)
+	case p.Platform.Valid() && !p.Platform.Supports(p.Protocol):
+		verr.addf("protocol", "%q is not supported on %s", p.Protocol, p.Platform)
+	}
+
+	validateCounts(verr, p.Platform, p.WatchCount, p.IPhoneCount)
+	p.SupportInfo.validate(verr, true)
+	return verr.Err()
+}
+
+// Validate checks the fields being changed. The platform is not part of an
+// update, so device counts are only range checked; use ValidateFor to also
+// check them against the template's platform.
+func (p UpdateTemplateParams) Validate() error {
+	return p.ValidateFor("")
+}
+
+// ValidateFor is like Validate but also checks that the device counts being
+// set are supported on platform
+func (p UpdateTemplateParams) ValidateFor(platform TemplatePlatform) error {
+	verr := &ValidationError{}
+	if p.CardTemplateID == "" {
+		verr.addf("card_template_id", "is required")
+	}
+
+	validateCounts(verr, platform, p.WatchCount, p.IPhoneCount)
+	if p.SupportInfo != nil {
+		p.SupportInfo.validate(verr, false)
+	}
+	return verr.Err()
+}
+
+// validateCounts checks device counts are in range and, if platform is
+// set, that the platform supports them
+func validateCounts(verr *ValidationError, platform TemplatePlatform, watchCount, iphoneCount int) {
+	counts := []struct {
+		field string
+		value int
+	}{
+		{"watch_count", watchCount},
+		{"iphone_count", iphoneCount},
+	}
+	for _, c := range counts {
+		switch {
+		case c.value < 0 || c.value > MaxDeviceCount:
+			verr.addf(c.field, "must be between 0 and %d", MaxDeviceCount)
+		case c.value != 0 && platform.Valid() && !platform.SupportsDeviceCounts():
+			verr.addf(c.field, "is not supported on %s", platform)
+		}
+	}
+}
+
+// validate checks support info fields. With required set, the support URL,
+// privacy policy and terms must all be present; otherwise only non-empty
+// fields are checked.
+func (s SupportInfo) validate(verr *ValidationError, required bool) {
+	urls := []struct {
+		field string
+		value string
+	}{
+		{"support_info.support_url", s.SupportURL},
+		{"support_info.privacy_policy_url", s.PrivacyPolicyURL},
+		{"support_info.terms_and_conditions_url", s.TermsAndConditionsURL},
+	}
+	for _, u := range urls {
+		if u.value == "" {
+			if required {
+				verr.addf(u.field, "is required")
+			}
+			continue
+		}
+		if !validURL(u.value) {
+			verr.addf(u.field, "must be an absolute http or https URL")
+		}
+	}
+
+	if s.SupportEmail != "" && !validEmail(s.SupportEmail) {
+		verr.addf("support_info.support_email", "is not a valid email address")
+	}
+	if s.SupportPhoneNumber != "" && !validPhone(s.SupportPhoneNumber) {
+		verr.addf("support_info.support_phone_number", "is not a valid phone number")
+	}
+}
+
+func validURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// validEmail accepts a bare address such as "support@example.com"
+func validEmail(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	return err == nil && addr.Address == s && addr.Name == ""
+}
+
+// validPhone accepts 7 to 15 digits with an optional leading "+" and
+// spaces, dashes, dots or parentheses as separators
+func validPhone(s string) bool {
+	digits := 0
+	for i, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case r == '+' && i == 0:
+		case strings.ContainsRune(" -.()", r):
+		default:
+			return false
+		}
+	}
+	return digits >= 7 && digits <= 15
+}
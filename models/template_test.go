@@ -0,0 +1,186 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func validCreateTemplateParams() CreateTemplateParams {
+	return CreateTemplateParams{
+		Name:        "Employee NFC key",
+		Platform:    TemplatePlatformApple,
+		UseCase:     UseCaseEmployeeBadge,
+		Protocol:    ProtocolDESFire,
+		WatchCount:  2,
+		IPhoneCount: 3,
+		SupportInfo: SupportInfo{
+			SupportURL:            "https://help.example.com",
+			SupportPhoneNumber:    "+1-555-123-4567",
+			SupportEmail:          "support@example.com",
+			PrivacyPolicyURL:      "https://example.com/privacy",
+			TermsAndConditionsURL: "https://example.com/terms",
+		},
+	}
+}
+
+func TestTemplatePlatform_Compatibility(t *testing.T) {
+	tests := []struct {
+		platform     TemplatePlatform
+		protocol     TemplateProtocol
+		supported    bool
+		deviceCounts bool
+	}{
+		{TemplatePlatformApple, ProtocolDESFire, true, true},
+		{TemplatePlatformApple, ProtocolSEOS, true, true},
+		{TemplatePlatformApple, ProtocolSmartTap, false, true},
+		{TemplatePlatformGoogle, ProtocolSmartTap, true, false},
+		{TemplatePlatformGoogle, ProtocolSEOS, false, false},
+		{TemplatePlatformAndroid, ProtocolSmartTap, true, false},
+		{"kiosk", ProtocolDESFire, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.platform)+"/"+string(tt.protocol), func(t *testing.T) {
+			if got := tt.platform.Supports(tt.protocol); got != tt.supported {
+				t.Errorf("Supports() = %v, want %v", got, tt.supported)
+			}
+			if got := tt.platform.SupportsDeviceCounts(); got != tt.deviceCounts {
+				t.Errorf("SupportsDeviceCounts() = %v, want %v", got, tt.deviceCounts)
+			}
+		})
+	}
+}
+
+func TestCreateTemplateParams_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		modify     func(*CreateTemplateParams)
+		wantFields []string
+	}{
+		{name: "Valid", modify: func(*CreateTemplateParams) {}},
+		{
+			name:       "Unknown enums",
+			modify:     func(p *CreateTemplateParams) { p.Platform, p.UseCase, p.Protocol = "kiosk", "parking", "mifare" },
+			wantFields: []string{"platform", "use_case", "protocol"},
+		},
+		{
+			name:       "Protocol not supported on platform",
+			modify:     func(p *CreateTemplateParams) { p.Protocol = ProtocolSmartTap },
+			wantFields: []string{"protocol"},
+		},
+		{
+			name: "Device counts on Google",
+			modify: func(p *CreateTemplateParams) {
+				p.Platform = TemplatePlatformGoogle
+			},
+			wantFields: []string{"watch_count", "iphone_count"},
+		},
+		{
+			name:       "Counts out of range",
+			modify:     func(p *CreateTemplateParams) { p.WatchCount, p.IPhoneCount = -1, MaxDeviceCount+1 },
+			wantFields: []string{"watch_count", "iphone_count"},
+		},
+		{
+			name: "Missing and malformed support info",
+			modify: func(p *CreateTemplateParams) {
+				p.SupportInfo.SupportURL = ""
+				p.SupportInfo.PrivacyPolicyURL = "example.com/privacy"
+				p.SupportInfo.SupportEmail = "Support <support@example.com>"
+				p.SupportInfo.SupportPhoneNumber = "call us"
+			},
+			wantFields: []string{
+				"support_info.support_url",
+				"support_info.privacy_policy_url",
+				"support_info.support_email",
+				"support_info.support_phone_number",
+			},
+		},
+		{
+			name:       "Missing name",
+			modify:     func(p *CreateTemplateParams) { p.Name = " " },
+			wantFields: []string{"name"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := validCreateTemplateParams()
+			tt.modify(&params)
+
+			err := params.Validate()
+			if len(tt.wantFields) == 0 {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Validate() error = %v, want *ValidationError", err)
+			}
+			if len(verr.Errors) != len(tt.wantFields) {
+				t.Errorf("Validate() errors = %+v, want fields %v", verr.Errors, tt.wantFields)
+			}
+			for _, field := range tt.wantFields {
+				if !verr.HasField(field) {
+					t.Errorf("Validate() missing error for %s: %v", field, err)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateTemplateParams_Validate(t *testing.T) {
+	params := UpdateTemplateParams{
+		CardTemplateID: "0xd3adb00b5",
+		WatchCount:     2,
+		SupportInfo:    &SupportInfo{SupportEmail: "support@example.com"},
+	}
+	if err := params.Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want partial support info accepted", err)
+	}
+
+	var verr *ValidationError
+	if err := params.ValidateFor(TemplatePlatformGoogle); !errors.As(err, &verr) || !verr.HasField("watch_count") {
+		t.Errorf("ValidateFor(google) error = %v, want watch_count error", err)
+	}
+
+	params.CardTemplateID = ""
+	params.SupportInfo.SupportURL = "ftp://example.com"
+	if err := params.Validate(); !errors.As(err, &verr) || !verr.HasField("card_template_id") || !verr.HasField("support_info.support_url") {
+		t.Errorf("Validate() error = %v, want card_template_id and support_url errors", err)
+	}
+}
+
+func TestDiffTemplates(t *testing.T) {
+	from := Template{
+		ID:          "0xd3adb00b5",
+		Name:        "Employee NFC key",
+		WatchCount:  2,
+		Design:      TemplateDesign{BackgroundColor: "#FFFFFF"},
+		SupportInfo: SupportInfo{SupportEmail: "support@example.com"},
+	}
+	to := from
+	to.ID = "ignored"
+	to.WatchCount = 3
+	to.Design.BackgroundColor = "#000000"
+
+	changes := DiffTemplates(from, to)
+	want := []TemplateChange{
+		{Field: "watch_count", From: "2", To: "3"},
+		{Field: "design.background_color", From: "#FFFFFF", To: "#000000"},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("DiffTemplates() = %+v, want %+v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("DiffTemplates()[%d] = %+v, want %+v", i, changes[i], want[i])
+		}
+	}
+
+	if changes := DiffTemplates(from, from); len(changes) != 0 {
+		t.Errorf("DiffTemplates(same) = %+v, want none", changes)
+	}
+}
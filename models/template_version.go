@@ -0,0 +1,40 @@
+package models
+
+import "strconv"
+
+// templateFields lists the comparable fields of a template with their JSON
+// names
+func templateFields(t Template) [][2]string {
+	return [][2]string{
+		{"name", t.Name},
+		{"platform", string(t.Platform)},
+		{"use_case", string(t.UseCase)},
+		{"protocol", string(t.Protocol)},
+		{"watch_count", strconv.Itoa(t.WatchCount)},
+		{"iphone_count", strconv.Itoa(t.IPhoneCount)},
+		{"design.background_color", t.Design.BackgroundColor},
+		{"design.label_color", t.Design.LabelColor},
+		{"design.label_secondary_color", t.Design.LabelSecondaryColor},
+		{"design.background_image", t.Design.BackgroundImage},
+		{"design.logo_image", t.Design.LogoImage},
+		{"design.icon_image", t.Design.IconImage},
+		{"support_info.support_url", t.SupportInfo.SupportURL},
+		{"support_info.support_phone_number", t.SupportInfo.SupportPhoneNumber},
+		{"support_info.support_email", t.SupportInfo.SupportEmail},
+		{"support_info.privacy_policy_url", t.SupportInfo.PrivacyPolicyURL},
+		{"support_info.terms_and_conditions_url", t.SupportInfo.TermsAndConditionsURL},
+	}
+}
+
+// DiffTemplates lists the fields that differ between two templates, in a
+// fixed field order. IDs and timestamps are not compared.
+func DiffTemplates(from, to Template) []TemplateChange {
+	var changes []TemplateChange
+	toFields := templateFields(to)
+	for i, field := range templateFields(from) {
+		if field[1] != toFields[i][1] {
+			changes = append(changes, TemplateChange{Field: field[0], From: field[1], To: toFields[i][1]})
+		}
+	}
+	return changes
+}
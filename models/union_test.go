@@ -0,0 +1,148 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseUnion(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		wantUnified bool
+	}{
+		{
+			name:        "Card without details",
+			data:        `{"id": "0xc4rd1d", "state": "active"}`,
+			wantUnified: false,
+		},
+		{
+			name:        "Card with object details",
+			data:        `{"id": "0xc4rd1d", "details": {"note": "lobby"}}`,
+			wantUnified: false,
+		},
+		{
+			name:        "Unified pass with children",
+			data:        `{"id": "0xp4ss", "details": [{"id": "0xapple"}]}`,
+			wantUnified: true,
+		},
+		{
+			name:        "Unified pass with zero children",
+			data:        `{"id": "0xp4ss", "details": []}`,
+			wantUnified: true,
+		},
+		{
+			name:        "Card with unified-looking ID",
+			data:        `{"id": "uap_123", "state": "active"}`,
+			wantUnified: false,
+		},
+		{
+			name:        "Explicit card type overrides details shape",
+			data:        `{"type": "card", "id": "0xc4rd1d", "details": []}`,
+			wantUnified: false,
+		},
+		{
+			name:        "Explicit unified type",
+			data:        `{"type": "unified_access_pass", "id": "0xp4ss"}`,
+			wantUnified: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := ParseUnion([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("ParseUnion() error = %v", err)
+			}
+			_, unified := AsUnifiedAccessPass(u)
+			if unified != tt.wantUnified {
+				t.Errorf("ParseUnion() got %T, wantUnified %v", u, tt.wantUnified)
+			}
+		})
+	}
+}
+
+func TestUnionValueRoundTrip(t *testing.T) {
+	original := []UnionValue{
+		{Value: &Card{ID: "0xc4rd1d", State: "active", Details: map[string]interface{}{"note": "lobby"}}},
+		{Value: &UnifiedAccessPass{ID: "0xp4ss"}},
+	}
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	var reloaded []UnionValue
+	if err := json.Unmarshal(data, &reloaded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	card, ok := AsCard(reloaded[0].Value)
+	if !ok || card.ID != "0xc4rd1d" {
+		t.Errorf("reloaded[0] = %#v, want card 0xc4rd1d", reloaded[0].Value)
+	}
+	uap, ok := AsUnifiedAccessPass(reloaded[1].Value)
+	if !ok || uap.ID != "0xp4ss" {
+		t.Errorf("reloaded[1] = %#v, want unified pass 0xp4ss", reloaded[1].Value)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	describe := func(u Union) string {
+		return Match(u,
+			func(c *Card) string { return "card " + c.ID },
+			func(p *UnifiedAccessPass) string { return "pass " + p.ID },
+		)
+	}
+
+	if got := describe(&Card{ID: "0xc4rd1d"}); got != "card 0xc4rd1d" {
+		t.Errorf("Match() = %v, want %v", got, "card 0xc4rd1d")
+	}
+	if got := describe(&UnifiedAccessPass{ID: "0xp4ss"}); got != "pass 0xp4ss" {
+		t.Errorf("Match() = %v, want %v", got, "pass 0xp4ss")
+	}
+	if got := describe(nil); got != "" {
+		t.Errorf("Match(nil) = %v, want empty", got)
+	}
+}
+
+func TestParseUnion_DerivesState(t *testing.T) {
+	u, err := ParseUnion([]byte(`{"id": "0xp4ss", "details": [{"id": "0xa", "state": "active"}, {"id": "0xg", "state": "suspended"}]}`))
+	if err != nil {
+		t.Fatalf("ParseUnion() error = %v", err)
+	}
+	if u.GetState() != StateMixed {
+		t.Errorf("GetState() = %q, want %q", u.GetState(), StateMixed)
+	}
+}
+
+func TestUnionValue_NotAUnion(t *testing.T) {
+	var v interface{} = UnionValue{Value: &Card{ID: "0xc4rd1d"}}
+	if _, ok := v.(Union); ok {
+		t.Error("UnionValue satisfies Union; it should only wrap one")
+	}
+}
+
+func TestUnifiedAccessPass_GoogleCard(t *testing.T) {
+	tests := []struct {
+		name     string
+		platform DevicePlatform
+	}{
+		{"Google", DevicePlatformGoogle},
+		{"Android", DevicePlatformAndroid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uap := &UnifiedAccessPass{Details: []Card{
+				{ID: "0xapple", Platform: DevicePlatformApple},
+				{ID: "0xgoogle", Platform: tt.platform},
+			}}
+			card, ok := uap.GoogleCard()
+			if !ok || card.ID != "0xgoogle" {
+				t.Errorf("GoogleCard() = %v, %v, want 0xgoogle", card, ok)
+			}
+		})
+	}
+}
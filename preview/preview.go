@@ -0,0 +1,300 @@
+// Package preview renders an offline PNG approximation of how a template's
+// design will look in Apple Wallet and Google Wallet
+package preview
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"time"
+
+	"github.com/Access-Grid/accessgrid-go/models"
+	"github.com/Access-Grid/accessgrid-go/templatedesign"
+)
+
+// Layout selects which wallet's pass layout to approximate
+type Layout string
+
+// Supported layouts
+const (
+	LayoutApple  Layout = "apple"
+	LayoutGoogle Layout = "google"
+)
+
+// LayoutFor returns the layout for a template platform. Android templates
+// use the Google layout.
+func LayoutFor(platform models.TemplatePlatform) (Layout, error) {
+	switch platform.Canonical() {
+	case models.TemplatePlatformApple:
+		return LayoutApple, nil
+	case models.TemplatePlatformGoogle:
+		return LayoutGoogle, nil
+	}
+	return "", fmt.Errorf("no preview layout for platform %q", platform)
+}
+
+// Sample is the cardholder data shown on a preview
+type Sample struct {
+	Organization   string
+	FullName       string
+	EmployeeID     string
+	Classification string
+	ExpirationDate time.Time
+}
+
+// DefaultSample returns placeholder cardholder data
+func DefaultSample() Sample {
+	return Sample{
+		Organization:   "Acme Corp",
+		FullName:       "Employee name",
+		EmployeeID:     "123456789",
+		Classification: "full_time",
+		ExpirationDate: time.Date(2030, time.December, 31, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// Default colors used when a design leaves them empty
+var (
+	defaultBackground     = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	defaultLabel          = color.RGBA{0x00, 0x00, 0x00, 0xff}
+	defaultLabelSecondary = color.RGBA{0x66, 0x66, 0x66, 0xff}
+)
+
+const (
+	margin       = 24
+	cornerRadius = 24
+)
+
+// palette holds a design's decoded colors and images
+type palette struct {
+	background     color.RGBA
+	label          color.RGBA
+	labelSecondary color.RGBA
+	backgroundImg  image.Image
+	logo           image.Image
+	icon           image.Image
+}
+
+func newPalette(design models.TemplateDesign) (*palette, error) {
+	p := &palette{
+		background:     defaultBackground,
+		label:          defaultLabel,
+		labelSecondary: defaultLabelSecondary,
+	}
+
+	colors := []struct {
+		field string
+		value string
+		dst   *color.RGBA
+	}{
+		{"background_color", design.BackgroundColor, &p.background},
+		{"label_color", design.LabelColor, &p.label},
+		{"label_secondary_color", design.LabelSecondaryColor, &p.labelSecondary},
+	}
+	for _, c := range colors {
+		if c.value == "" {
+			continue
+		}
+		parsed, err := templatedesign.ParseHexColor(c.value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", c.field, err)
+		}
+		*c.dst = parsed
+	}
+
+	images := []struct {
+		field string
+		value string
+		dst   *image.Image
+	}{
+		{"background_image", design.BackgroundImage, &p.backgroundImg},
+		{"logo_image", design.LogoImage, &p.logo},
+		{"icon_image", design.IconImage, &p.icon},
+	}
+	for _, img := range images {
+		if img.value == "" {
+			continue
+		}
+		decoded, err := templatedesign.DecodeImage(img.value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", img.field, err)
+		}
+		*img.dst = decoded
+	}
+	return p, nil
+}
+
+// Render draws a preview of design with sample data in the given layout
+func Render(design models.TemplateDesign, layout Layout, sample Sample) (image.Image, error) {
+	p, err := newPalette(design)
+	if err != nil {
+		return nil, fmt.Errorf("error rendering preview: %w", err)
+	}
+
+	switch layout {
+	case LayoutApple:
+		return roundCorners(renderApple(p, sample)), nil
+	case LayoutGoogle:
+		return roundCorners(renderGoogle(p, sample)), nil
+	}
+	return nil, fmt.Errorf("unknown preview layout %q", layout)
+}
+
+// RenderTemplate draws a preview of a template in the layout for its
+// platform
+func RenderTemplate(template models.Template, sample Sample) (image.Image, error) {
+	layout, err := LayoutFor(template.Platform)
+	if err != nil {
+		return nil, err
+	}
+	return Render(template.Design, layout, sample)
+}
+
+// RenderPNG renders a preview and writes it to w as PNG
+func RenderPNG(w io.Writer, design models.TemplateDesign, layout Layout, sample Sample) error {
+	img, err := Render(design, layout, sample)
+	if err != nil {
+		return err
+	}
+	return png.Encode(w, img)
+}
+
+// renderApple approximates an Apple Wallet access pass: logo and expiration
+// across the top, the cardholder's name as the primary field and secondary
+// fields below it, over the background image
+func renderApple(p *palette, sample Sample) *image.RGBA {
+	card := image.NewRGBA(image.Rect(0, 0, 640, 404))
+	fill(card, card.Bounds(), p.background)
+	if p.backgroundImg != nil {
+		drawCover(card, card.Bounds(), p.backgroundImg)
+	}
+	if p.logo != nil {
+		drawFit(card, image.Rect(margin, margin, margin+160, margin+50), p.logo, false)
+	}
+
+	expires := sample.ExpirationDate.Format("01/02/2006")
+	right := card.Bounds().Dx() - margin
+	drawText(card, image.Pt(right-textWidth("EXPIRES", 2), margin), "EXPIRES", 2, p.labelSecondary)
+	drawText(card, image.Pt(right-textWidth(expires, 3), margin+20), expires, 3, p.label)
+
+	width := card.Bounds().Dx() - 2*margin
+	drawText(card, image.Pt(margin, 120), "NAME", 2, p.labelSecondary)
+	drawText(card, image.Pt(margin, 142), truncate(sample.FullName, 5, width), 5, p.label)
+
+	half := width / 2
+	drawField(card, image.Pt(margin, 240), "EMPLOYEE ID", sample.EmployeeID, half, p)
+	drawField(card, image.Pt(margin+half, 240), "CLASSIFICATION", sample.Classification, half, p)
+
+	// The icon appears in notifications and on the lock screen rather than
+	// on the pass; show it in the corner for reference
+	if p.icon != nil {
+		bottom := card.Bounds().Dy() - margin
+		drawFit(card, image.Rect(right-40, bottom-40, right, bottom), p.icon, false)
+	}
+	return card
+}
+
+// renderGoogle approximates a Google Wallet generic pass: a header with the
+// logo and organization, the cardholder's name as the title, fields below
+// it and the background image as the hero image along the bottom
+func renderGoogle(p *palette, sample Sample) *image.RGBA {
+	card := image.NewRGBA(image.Rect(0, 0, 640, 480))
+	fill(card, card.Bounds(), p.background)
+
+	logo := p.logo
+	if logo == nil {
+		logo = p.icon
+	}
+	if logo != nil {
+		drawFit(card, image.Rect(margin, margin, margin+56, margin+56), logo, true)
+	}
+
+	width := card.Bounds().Dx() - 2*margin
+	drawText(card, image.Pt(margin+72, margin+21), truncate(sample.Organization, 2, width-72), 2, p.label)
+	drawText(card, image.Pt(margin, 108), truncate(sample.FullName, 5, width), 5, p.label)
+
+	half := width / 2
+	drawField(card, image.Pt(margin, 180), "EMPLOYEE ID", sample.EmployeeID, half, p)
+	drawField(card, image.Pt(margin+half, 180), "EXPIRES", sample.ExpirationDate.Format("01/02/2006"), half, p)
+
+	if p.backgroundImg != nil {
+		drawCover(card, image.Rect(0, 272, 640, 480), p.backgroundImg)
+	}
+	return card
+}
+
+// drawField draws a label and its value, truncated to width
+func drawField(dst *image.RGBA, pt image.Point, label, value string, width int, p *palette) {
+	drawText(dst, pt, truncate(label, 2, width-margin), 2, p.labelSecondary)
+	drawText(dst, pt.Add(image.Pt(0, 22)), truncate(value, 3, width-margin), 3, p.label)
+}
+
+func fill(dst *image.RGBA, r image.Rectangle, c color.Color) {
+	draw.Draw(dst, r, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+// drawCover scales img to cover r and crops the overflow
+func drawCover(dst *image.RGBA, r image.Rectangle, img image.Image) {
+	b := img.Bounds()
+	scale := max(float64(r.Dx())/float64(b.Dx()), float64(r.Dy())/float64(b.Dy()))
+	w := max(r.Dx(), int(float64(b.Dx())*scale+0.5))
+	h := max(r.Dy(), int(float64(b.Dy())*scale+0.5))
+	scaled := templatedesign.Resize(img, w, h)
+
+	offset := image.Pt((w-r.Dx())/2, (h-r.Dy())/2)
+	draw.Draw(dst, r, scaled, offset, draw.Over)
+}
+
+// drawFit scales img to fit within r, anchored to its top-left corner, and
+// optionally clips it to a circle
+func drawFit(dst *image.RGBA, r image.Rectangle, img image.Image, circle bool) {
+	fitted := templatedesign.Fit(img, templatedesign.Constraint{Width: r.Dx(), Height: r.Dy()}, color.Transparent)
+	if fitted.Bounds().Dx() < r.Dx() || fitted.Bounds().Dy() < r.Dy() {
+		// Enlarge small images to fill the box
+		scale := min(float64(r.Dx())/float64(fitted.Bounds().Dx()), float64(r.Dy())/float64(fitted.Bounds().Dy()))
+		fitted = templatedesign.Resize(fitted, int(float64(fitted.Bounds().Dx())*scale), int(float64(fitted.Bounds().Dy())*scale))
+	}
+
+	target := fitted.Bounds().Sub(fitted.Bounds().Min).Add(r.Min)
+	if circle {
+		draw.DrawMask(dst, target, fitted, fitted.Bounds().Min, &roundedMask{rect: target, radius: min(target.Dx(), target.Dy()) / 2}, target.Min, draw.Over)
+		return
+	}
+	draw.Draw(dst, target, fitted, fitted.Bounds().Min, draw.Over)
+}
+
+// roundCorners makes the corners of a card transparent
+func roundCorners(card *image.RGBA) *image.RGBA {
+	out := image.NewRGBA(card.Bounds())
+	draw.DrawMask(out, out.Bounds(), card, image.Point{}, &roundedMask{rect: card.Bounds(), radius: cornerRadius}, image.Point{}, draw.Src)
+	return out
+}
+
+// roundedMask is an alpha mask for a rectangle with rounded corners
+type roundedMask struct {
+	rect   image.Rectangle
+	radius int
+}
+
+func (m *roundedMask) ColorModel() color.Model { return color.AlphaModel }
+
+func (m *roundedMask) Bounds() image.Rectangle { return m.rect }
+
+func (m *roundedMask) At(x, y int) color.Color {
+	if !(image.Point{x, y}.In(m.rect)) {
+		return color.Alpha{}
+	}
+
+	// Distance from the nearest corner circle's center, if the point lies
+	// in a corner region
+	cx := min(max(x, m.rect.Min.X+m.radius), m.rect.Max.X-m.radius-1)
+	cy := min(max(y, m.rect.Min.Y+m.radius), m.rect.Max.Y-m.radius-1)
+	dx, dy := x-cx, y-cy
+	if dx*dx+dy*dy > m.radius*m.radius {
+		return color.Alpha{}
+	}
+	return color.Alpha{A: 0xff}
+}
@@ -0,0 +1,165 @@
+package preview
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/Access-Grid/accessgrid-go/models"
+)
+
+func encodedSolid(w, h int, c color.Color) string {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func rgbaAt(img image.Image, x, y int) color.RGBA {
+	return color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+}
+
+func countColor(img image.Image, c color.RGBA) int {
+	n := 0
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if rgbaAt(img, x, y) == c {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func TestRender(t *testing.T) {
+	navy := color.RGBA{0x00, 0x00, 0x80, 0xff}
+	yellow := color.RGBA{0xff, 0xff, 0x00, 0xff}
+	red := color.RGBA{0xff, 0x00, 0x00, 0xff}
+	green := color.RGBA{0x00, 0xff, 0x00, 0xff}
+
+	design := models.TemplateDesign{
+		BackgroundColor:     "#000080",
+		LabelColor:          "#FFFF00",
+		LabelSecondaryColor: "#CCCCCC",
+		LogoImage:           encodedSolid(64, 20, red),
+		IconImage:           encodedSolid(8, 8, green),
+	}
+
+	tests := []struct {
+		layout Layout
+		size   image.Point
+		logoAt image.Point
+	}{
+		{LayoutApple, image.Pt(640, 404), image.Pt(margin+5, margin+5)},
+		{LayoutGoogle, image.Pt(640, 480), image.Pt(margin+28, margin+14)},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.layout), func(t *testing.T) {
+			img, err := Render(design, tt.layout, DefaultSample())
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+
+			if img.Bounds().Size() != tt.size {
+				t.Errorf("Render() size = %v, want %v", img.Bounds().Size(), tt.size)
+			}
+			if a := rgbaAt(img, 0, 0).A; a != 0 {
+				t.Errorf("corner alpha = %d, want transparent", a)
+			}
+			if got := rgbaAt(img, 320, 100); got != navy {
+				t.Errorf("background = %v, want %v", got, navy)
+			}
+			if got := rgbaAt(img, tt.logoAt.X, tt.logoAt.Y); got != red {
+				t.Errorf("logo pixel = %v, want %v", got, red)
+			}
+			if countColor(img, yellow) == 0 {
+				t.Error("no text drawn in the label color")
+			}
+		})
+	}
+}
+
+func TestRender_BackgroundImage(t *testing.T) {
+	purple := color.RGBA{0x80, 0x00, 0x80, 0xff}
+	design := models.TemplateDesign{BackgroundImage: encodedSolid(1032, 336, purple)}
+
+	img, err := Render(design, LayoutGoogle, DefaultSample())
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if got := rgbaAt(img, 320, 400); got != purple {
+		t.Errorf("hero image pixel = %v, want %v", got, purple)
+	}
+	if got := rgbaAt(img, 320, 250); got != defaultBackground {
+		t.Errorf("pixel above hero = %v, want default background", got)
+	}
+}
+
+func TestRender_InvalidDesign(t *testing.T) {
+	tests := []struct {
+		name   string
+		design models.TemplateDesign
+		field  string
+	}{
+		{"Bad color", models.TemplateDesign{LabelColor: "yellow"}, "label_color"},
+		{"Bad image", models.TemplateDesign{LogoImage: "not base64!"}, "logo_image"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Render(tt.design, LayoutApple, DefaultSample())
+			if err == nil || !strings.Contains(err.Error(), tt.field) {
+				t.Errorf("Render() error = %v, want error naming %s", err, tt.field)
+			}
+		})
+	}
+
+	if _, err := Render(models.TemplateDesign{}, "windows", DefaultSample()); err == nil {
+		t.Error("Render() error = nil for unknown layout")
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	img, err := RenderTemplate(models.Template{Platform: "android"}, DefaultSample())
+	if err != nil {
+		t.Fatalf("RenderTemplate() error = %v", err)
+	}
+	if img.Bounds().Dy() != 480 {
+		t.Errorf("RenderTemplate() height = %d, want the Google layout", img.Bounds().Dy())
+	}
+
+	if _, err := RenderTemplate(models.Template{Platform: "kiosk"}, DefaultSample()); err == nil {
+		t.Error("RenderTemplate() error = nil for unknown platform")
+	}
+}
+
+func TestRenderPNG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RenderPNG(&buf, models.TemplateDesign{}, LayoutApple, DefaultSample()); err != nil {
+		t.Fatalf("RenderPNG() error = %v", err)
+	}
+	if _, err := png.Decode(&buf); err != nil {
+		t.Errorf("RenderPNG() wrote invalid PNG: %v", err)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := truncate("SHORT", 1, 100); got != "SHORT" {
+		t.Errorf("truncate() = %q", got)
+	}
+	got := truncate("A VERY LONG NAME INDEED", 1, 60)
+	if !strings.HasSuffix(got, "..") || textWidth(got, 1) > 60 {
+		t.Errorf("truncate() = %q (width %d)", got, textWidth(got, 1))
+	}
+}
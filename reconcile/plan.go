@@ -0,0 +1,95 @@
+package reconcile
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Access-Grid/accessgrid-go/models"
+)
+
+// Action is a change the reconciler makes to bring a card in line with the
+// desired state
+type Action string
+
+// Plan actions
+const (
+	ActionCreate  Action = "create"
+	ActionUpdate  Action = "update"
+	ActionSuspend Action = "suspend"
+	ActionResume  Action = "resume"
+	ActionDelete  Action = "delete"
+)
+
+// FieldChange describes a single attribute that differs between the desired
+// and actual card
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// Operation is a single planned change
+type Operation struct {
+	Action     Action                  `json:"action"`
+	EmployeeID string                  `json:"employee_id"`
+	TemplateID string                  `json:"template_id"`
+	CardID     string                  `json:"card_id,omitempty"`
+	Changes    []FieldChange           `json:"changes,omitempty"`
+	Provision  *models.ProvisionParams `json:"provision,omitempty"`
+	Update     *models.UpdateParams    `json:"update,omitempty"`
+}
+
+// Plan is the set of operations needed to make AccessGrid match the desired
+// state. It can be serialized with encoding/json for review and loaded again
+// before Apply.
+type Plan struct {
+	GeneratedAt time.Time   `json:"generated_at"`
+	Templates   []string    `json:"templates"`
+	ActualCount int         `json:"actual_count"`
+	Operations  []Operation `json:"operations"`
+}
+
+// Count returns the number of operations with the given action
+func (p *Plan) Count(action Action) int {
+	n := 0
+	for _, op := range p.Operations {
+		if op.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// Empty reports whether the plan makes no changes
+func (p *Plan) Empty() bool {
+	return len(p.Operations) == 0
+}
+
+// Write prints the plan in a human-readable form
+func (p *Plan) Write(w io.Writer) error {
+	fmt.Fprintf(w, "Plan: %d to create, %d to update, %d to suspend, %d to resume, %d to delete (%d existing cards)\n",
+		p.Count(ActionCreate), p.Count(ActionUpdate), p.Count(ActionSuspend), p.Count(ActionResume), p.Count(ActionDelete), p.ActualCount)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, op := range p.Operations {
+		var details []string
+		for _, c := range op.Changes {
+			details = append(details, fmt.Sprintf("%s: %q -> %q", c.Field, c.From, c.To))
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", op.Action, op.EmployeeID, op.TemplateID, op.CardID, strings.Join(details, ", "))
+	}
+	return tw.Flush()
+}
+
+// LoadPlan decodes a plan previously serialized with encoding/json
+func LoadPlan(r io.Reader) (*Plan, error) {
+	var plan Plan
+	if err := json.NewDecoder(r).Decode(&plan); err != nil {
+		return nil, fmt.Errorf("error decoding plan: %w", err)
+	}
+	return &plan, nil
+}
@@ -0,0 +1,464 @@
+// Package reconcile makes the cards in AccessGrid match a desired set of
+// cardholders, such as an HR roster. Changes are computed as a Plan that can
+// be reviewed, serialized and applied with safety limits.
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Access-Grid/accessgrid-go/models"
+	"github.com/Access-Grid/accessgrid-go/services"
+)
+
+// DefaultConcurrency is the number of operations applied in parallel unless
+// configured otherwise
+const DefaultConcurrency = 4
+
+// ErrLimitExceeded is returned by Apply when a plan breaches its safety
+// limits. No changes are made in that case.
+var ErrLimitExceeded = errors.New("plan exceeds safety limits")
+
+// Cardholder is the desired state of one employee's card on one template.
+// Empty attributes are left as they are on existing cards.
+type Cardholder struct {
+	EmployeeID     string
+	TemplateID     string
+	FullName       string
+	Email          string
+	PhoneNumber    string
+	Classification string
+	CardNumber     string
+	SiteCode       string
+	StartDate      time.Time
+	ExpirationDate time.Time
+	// Suspended requests that the cardholder's card exist but be suspended
+	Suspended bool
+}
+
+// Limits guards Apply against plans that would remove too many cards. Zero
+// values use the corresponding value from DefaultLimits; a negative value
+// disables the check.
+type Limits struct {
+	// MaxDeletePercent aborts if deletes exceed this percentage of
+	// existing cards
+	MaxDeletePercent float64
+	// MaxSuspendPercent aborts if suspends exceed this percentage of
+	// existing cards
+	MaxSuspendPercent float64
+	// MaxOperations aborts if the plan has more operations than this
+	MaxOperations int
+}
+
+// DefaultLimits are conservative limits suitable for routine syncs
+var DefaultLimits = Limits{MaxDeletePercent: 10, MaxSuspendPercent: 25}
+
+// NoLimits disables every safety check
+var NoLimits = Limits{MaxDeletePercent: -1, MaxSuspendPercent: -1, MaxOperations: -1}
+
+// withDefaults fills zero fields from DefaultLimits
+func (l Limits) withDefaults() Limits {
+	if l.MaxDeletePercent == 0 {
+		l.MaxDeletePercent = DefaultLimits.MaxDeletePercent
+	}
+	if l.MaxSuspendPercent == 0 {
+		l.MaxSuspendPercent = DefaultLimits.MaxSuspendPercent
+	}
+	if l.MaxOperations == 0 {
+		l.MaxOperations = DefaultLimits.MaxOperations
+	}
+	return l
+}
+
+// Result is the outcome of applying a single operation
+type Result struct {
+	Operation
+	Error string `json:"error,omitempty"`
+}
+
+// Report summarizes an applied plan
+type Report struct {
+	Results   []Result `json:"results"`
+	Succeeded int      `json:"succeeded"`
+	Failed    int      `json:"failed"`
+}
+
+// Reconciler plans and applies changes that make AccessGrid match a desired
+// set of cardholders
+type Reconciler struct {
+	cards        *services.AccessCardsService
+	templates    []string
+	orphanAction Action
+	concurrency  int
+	now          func() time.Time
+}
+
+// Option allows for customizing the reconciler
+type Option func(*Reconciler)
+
+// WithTemplates adds templates to the reconciled scope. Cards on these
+// templates with no matching cardholder are treated as orphans even if no
+// cardholder references the template. By default only templates referenced
+// by the desired set are in scope.
+func WithTemplates(templateIDs ...string) Option {
+	return func(r *Reconciler) {
+		r.templates = append(r.templates, templateIDs...)
+	}
+}
+
+// WithOrphanAction sets what happens to cards with no matching cardholder:
+// ActionSuspend (the default) or ActionDelete
+func WithOrphanAction(action Action) Option {
+	return func(r *Reconciler) {
+		r.orphanAction = action
+	}
+}
+
+// WithConcurrency sets how many operations are applied in parallel
+func WithConcurrency(n int) Option {
+	return func(r *Reconciler) {
+		r.concurrency = n
+	}
+}
+
+// NewReconciler creates a new Reconciler
+func NewReconciler(cards *services.AccessCardsService, options ...Option) (*Reconciler, error) {
+	if cards == nil {
+		return nil, errors.New("access cards service is required")
+	}
+
+	reconciler := &Reconciler{
+		cards:        cards,
+		orphanAction: ActionSuspend,
+		concurrency:  DefaultConcurrency,
+		now:          time.Now,
+	}
+
+	// Apply any custom options
+	for _, option := range options {
+		option(reconciler)
+	}
+
+	if reconciler.orphanAction != ActionSuspend && reconciler.orphanAction != ActionDelete {
+		return nil, fmt.Errorf("unsupported orphan action %q", reconciler.orphanAction)
+	}
+	if reconciler.concurrency < 1 {
+		reconciler.concurrency = 1
+	}
+
+	return reconciler, nil
+}
+
+type cardKey struct {
+	employeeID string
+	templateID string
+}
+
+// Plan compares the desired cardholders with the cards currently issued on
+// the in-scope templates and returns the operations needed to reconcile
+// them. Nothing is changed.
+func (r *Reconciler) Plan(ctx context.Context, desired []Cardholder) (*Plan, error) {
+	wanted := make(map[cardKey]Cardholder, len(desired))
+	scope := make(map[string]bool)
+	for _, templateID := range r.templates {
+		scope[templateID] = true
+	}
+	for i, holder := range desired {
+		if holder.EmployeeID == "" || holder.TemplateID == "" {
+			return nil, fmt.Errorf("cardholder %d: employee ID and template ID are required", i)
+		}
+		key := cardKey{holder.EmployeeID, holder.TemplateID}
+		if _, ok := wanted[key]; ok {
+			return nil, fmt.Errorf("cardholder %d: duplicate entry for employee %s on template %s", i, holder.EmployeeID, holder.TemplateID)
+		}
+		wanted[key] = holder
+		scope[holder.TemplateID] = true
+	}
+
+	plan := &Plan{GeneratedAt: r.now()}
+	for templateID := range scope {
+		plan.Templates = append(plan.Templates, templateID)
+	}
+	sort.Strings(plan.Templates)
+
+	actual := make(map[cardKey]models.Card)
+	var orphans []models.Card
+	for _, templateID := range plan.Templates {
+		cards, err := r.cards.List(ctx, &models.ListKeysParams{TemplateID: templateID})
+		if err != nil {
+			return nil, fmt.Errorf("error listing cards for template %s: %w", templateID, err)
+		}
+		for _, card := range cards {
+			if card.CardTemplateID != templateID || card.State == models.CardStateDeleted {
+				continue
+			}
+			plan.ActualCount++
+
+			key := cardKey{card.EmployeeID, card.CardTemplateID}
+			existing, seen := actual[key]
+			_, isWanted := wanted[key]
+			switch {
+			case !isWanted:
+				orphans = append(orphans, card)
+			case !seen:
+				actual[key] = card
+			case existing.State != models.CardStateActive && card.State == models.CardStateActive:
+				// Prefer keeping an active duplicate over a suspended one
+				actual[key] = card
+				orphans = append(orphans, existing)
+			default:
+				orphans = append(orphans, card)
+			}
+		}
+	}
+
+	keys := make([]cardKey, 0, len(wanted))
+	for key := range wanted {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].employeeID != keys[j].employeeID {
+			return keys[i].employeeID < keys[j].employeeID
+		}
+		return keys[i].templateID < keys[j].templateID
+	})
+
+	for _, key := range keys {
+		holder := wanted[key]
+		card, ok := actual[key]
+		if !ok {
+			if !holder.Suspended {
+				plan.Operations = append(plan.Operations, createOperation(holder))
+			}
+			continue
+		}
+
+		if op, ok := updateOperation(holder, card); ok {
+			plan.Operations = append(plan.Operations, op)
+		}
+
+		switch {
+		case holder.Suspended && card.State == models.CardStateActive:
+			plan.Operations = append(plan.Operations, cardOperation(ActionSuspend, card))
+		case !holder.Suspended && card.State == models.CardStateSuspended:
+			plan.Operations = append(plan.Operations, cardOperation(ActionResume, card))
+		}
+	}
+
+	sort.Slice(orphans, func(i, j int) bool { return orphans[i].ID < orphans[j].ID })
+	for _, card := range orphans {
+		if r.orphanAction == ActionSuspend && card.State == models.CardStateSuspended {
+			continue
+		}
+		plan.Operations = append(plan.Operations, cardOperation(r.orphanAction, card))
+	}
+
+	return plan, nil
+}
+
+// Apply carries out every operation in the plan. If the plan breaches
+// limits, Apply returns an error wrapping ErrLimitExceeded without making
+// any change. Otherwise all operations are attempted; the returned error
+// joins any failures.
+//
+// Operations on the same card, such as an update followed by a suspend, run
+// in plan order within one worker. If one fails, the card's remaining
+// operations are skipped and reported as failed. Different cards are
+// reconciled concurrently.
+func (r *Reconciler) Apply(ctx context.Context, plan *Plan, limits Limits) (*Report, error) {
+	if err := checkLimits(plan, limits.withDefaults()); err != nil {
+		return nil, err
+	}
+
+	report := &Report{Results: make([]Result, len(plan.Operations))}
+	errs := make([]error, len(plan.Operations))
+
+	sem := make(chan struct{}, r.concurrency)
+	var wg sync.WaitGroup
+	for _, group := range groupByCard(plan.Operations) {
+		wg.Add(1)
+		go func(group []int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			var failed string
+			for _, i := range group {
+				op := plan.Operations[i]
+				var err error
+				if failed != "" {
+					err = fmt.Errorf("skipped after %s failed", failed)
+				} else {
+					op.CardID, err = r.apply(ctx, op)
+				}
+				report.Results[i] = Result{Operation: op}
+				if err != nil {
+					if failed == "" {
+						failed = string(op.Action)
+					}
+					report.Results[i].Error = err.Error()
+					errs[i] = fmt.Errorf("%s employee %s on template %s: %w", op.Action, op.EmployeeID, op.TemplateID, err)
+				}
+			}
+		}(group)
+	}
+	wg.Wait()
+
+	for _, result := range report.Results {
+		if result.Error == "" {
+			report.Succeeded++
+		} else {
+			report.Failed++
+		}
+	}
+
+	return report, errors.Join(errs...)
+}
+
+// groupByCard returns the indexes of ops grouped by card, each group in plan
+// order. Creates have no card yet and form groups of their own.
+func groupByCard(ops []Operation) [][]int {
+	var groups [][]int
+	byCard := make(map[string]int)
+	for i, op := range ops {
+		if op.CardID == "" {
+			groups = append(groups, []int{i})
+			continue
+		}
+		g, ok := byCard[op.CardID]
+		if !ok {
+			g = len(groups)
+			byCard[op.CardID] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], i)
+	}
+	return groups
+}
+
+// apply carries out a single operation and returns the affected card ID
+func (r *Reconciler) apply(ctx context.Context, op Operation) (string, error) {
+	switch op.Action {
+	case ActionCreate:
+		if op.Provision == nil {
+			return "", errors.New("create operation has no provision parameters")
+		}
+		card, err := r.cards.Provision(ctx, *op.Provision)
+		if err != nil {
+			return "", err
+		}
+		return card.GetID(), nil
+	case ActionUpdate:
+		if op.Update == nil {
+			return op.CardID, errors.New("update operation has no update parameters")
+		}
+		_, err := r.cards.Update(ctx, *op.Update)
+		return op.CardID, err
+	case ActionSuspend:
+		return op.CardID, r.cards.Suspend(ctx, op.CardID)
+	case ActionResume:
+		return op.CardID, r.cards.Resume(ctx, op.CardID)
+	case ActionDelete:
+		return op.CardID, r.cards.Delete(ctx, op.CardID)
+	default:
+		return op.CardID, fmt.Errorf("unsupported action %q", op.Action)
+	}
+}
+
+func checkLimits(plan *Plan, limits Limits) error {
+	if limits.MaxOperations > 0 && len(plan.Operations) > limits.MaxOperations {
+		return fmt.Errorf("%w: %d operations planned, limit is %d", ErrLimitExceeded, len(plan.Operations), limits.MaxOperations)
+	}
+	if err := checkPercent(plan, ActionDelete, limits.MaxDeletePercent); err != nil {
+		return err
+	}
+	return checkPercent(plan, ActionSuspend, limits.MaxSuspendPercent)
+}
+
+func checkPercent(plan *Plan, action Action, max float64) error {
+	count := plan.Count(action)
+	if max <= 0 || count == 0 {
+		return nil
+	}
+	if plan.ActualCount == 0 {
+		return fmt.Errorf("%w: %d cards to %s with no existing cards", ErrLimitExceeded, count, action)
+	}
+	percent := float64(count) / float64(plan.ActualCount) * 100
+	if percent > max {
+		return fmt.Errorf("%w: %d of %d cards (%.1f%%) to %s, limit is %.1f%%", ErrLimitExceeded, count, plan.ActualCount, percent, action, max)
+	}
+	return nil
+}
+
+func createOperation(holder Cardholder) Operation {
+	return Operation{
+		Action:     ActionCreate,
+		EmployeeID: holder.EmployeeID,
+		TemplateID: holder.TemplateID,
+		Provision: &models.ProvisionParams{
+			CardTemplateID: holder.TemplateID,
+			EmployeeID:     holder.EmployeeID,
+			CardNumber:     holder.CardNumber,
+			SiteCode:       holder.SiteCode,
+			FullName:       holder.FullName,
+			Email:          holder.Email,
+			PhoneNumber:    holder.PhoneNumber,
+			Classification: holder.Classification,
+			StartDate:      holder.StartDate,
+			ExpirationDate: holder.ExpirationDate,
+		},
+	}
+}
+
+// updateOperation compares the attributes the API can update and returns an
+// update operation if any differ
+func updateOperation(holder Cardholder, card models.Card) (Operation, bool) {
+	params := &models.UpdateParams{CardID: card.ID}
+	var changes []FieldChange
+
+	diff := func(field, want, have string, set func()) {
+		if want != "" && want != have {
+			changes = append(changes, FieldChange{Field: field, From: have, To: want})
+			set()
+		}
+	}
+	diff("full_name", holder.FullName, card.FullName, func() { params.FullName = holder.FullName })
+	diff("email", holder.Email, card.Email, func() { params.Email = holder.Email })
+	diff("phone_number", holder.PhoneNumber, card.PhoneNumber, func() { params.PhoneNumber = holder.PhoneNumber })
+	diff("classification", holder.Classification, card.Classification, func() { params.Classification = holder.Classification })
+
+	if !holder.ExpirationDate.IsZero() && !holder.ExpirationDate.Equal(card.ExpirationDate) {
+		expiration := holder.ExpirationDate
+		changes = append(changes, FieldChange{
+			Field: "expiration_date",
+			From:  card.ExpirationDate.Format(time.RFC3339),
+			To:    expiration.Format(time.RFC3339),
+		})
+		params.ExpirationDate = &expiration
+	}
+
+	if len(changes) == 0 {
+		return Operation{}, false
+	}
+	return Operation{
+		Action:     ActionUpdate,
+		EmployeeID: holder.EmployeeID,
+		TemplateID: holder.TemplateID,
+		CardID:     card.ID,
+		Changes:    changes,
+		Update:     params,
+	}, true
+}
+
+func cardOperation(action Action, card models.Card) Operation {
+	return Operation{
+		Action:     action,
+		EmployeeID: card.EmployeeID,
+		TemplateID: card.CardTemplateID,
+		CardID:     card.ID,
+	}
+}
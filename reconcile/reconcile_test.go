@@ -0,0 +1,207 @@
+package reconcile
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Access-Grid/accessgrid-go/client"
+	"github.com/Access-Grid/accessgrid-go/models"
+	"github.com/Access-Grid/accessgrid-go/services"
+)
+
+func setupReconcileTestServer() (*httptest.Server, *services.AccessCardsService, *sync.Map) {
+	calls := &sync.Map{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		calls.Store(r.Method+" "+r.URL.Path, true)
+		w.WriteHeader(http.StatusOK)
+
+		switch {
+		case r.URL.Path == "/v1/key-cards" && r.Method == http.MethodGet:
+			w.Write([]byte(`{
+				"keys": [
+					{"id": "0xa1", "card_template_id": "0xd3adb00b5", "employee_id": "100", "full_name": "Alice", "state": "active"},
+					{"id": "0xb1", "card_template_id": "0xd3adb00b5", "employee_id": "200", "full_name": "Bob", "state": "suspended"},
+					{"id": "0xc1", "card_template_id": "0xd3adb00b5", "employee_id": "300", "full_name": "Carol", "state": "active"},
+					{"id": "0xd1", "card_template_id": "0xd3adb00b5", "employee_id": "400", "full_name": "Dave", "state": "active"}
+				]
+			}`))
+		case r.URL.Path == "/v1/key-cards" && r.Method == http.MethodPost:
+			w.Write([]byte(`{"id": "0xe1", "state": "active"}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+
+	c, _ := client.NewClient("test-account", "test-secret", client.WithBaseURL(server.URL))
+	return server, services.NewAccessCardsService(c), calls
+}
+
+var testRoster = []Cardholder{
+	{EmployeeID: "100", TemplateID: "0xd3adb00b5", FullName: "Alice Smith"},
+	{EmployeeID: "200", TemplateID: "0xd3adb00b5", FullName: "Bob"},
+	{EmployeeID: "300", TemplateID: "0xd3adb00b5", FullName: "Carol", Suspended: true},
+	{EmployeeID: "500", TemplateID: "0xd3adb00b5", FullName: "Erin"},
+}
+
+func TestReconciler_Plan(t *testing.T) {
+	server, cards, _ := setupReconcileTestServer()
+	defer server.Close()
+
+	reconciler, err := NewReconciler(cards, WithOrphanAction(ActionDelete))
+	if err != nil {
+		t.Fatalf("NewReconciler() error = %v", err)
+	}
+
+	plan, err := reconciler.Plan(context.Background(), testRoster)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+
+	want := map[Action]int{ActionCreate: 1, ActionUpdate: 1, ActionResume: 1, ActionSuspend: 1, ActionDelete: 1}
+	for action, n := range want {
+		if got := plan.Count(action); got != n {
+			t.Errorf("Plan() %s count = %v, want %v", action, got, n)
+		}
+	}
+	if plan.ActualCount != 4 {
+		t.Errorf("Plan() ActualCount = %v, want %v", plan.ActualCount, 4)
+	}
+
+	var out bytes.Buffer
+	if err := plan.Write(&out); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !strings.Contains(out.String(), `full_name: "Alice" -> "Alice Smith"`) {
+		t.Errorf("Write() output missing update details:\n%s", out.String())
+	}
+}
+
+func TestReconciler_PlanRoundTripAndApply(t *testing.T) {
+	server, cards, calls := setupReconcileTestServer()
+	defer server.Close()
+
+	reconciler, _ := NewReconciler(cards)
+	plan, err := reconciler.Plan(context.Background(), testRoster)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+
+	data, err := json.Marshal(plan)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	loaded, err := LoadPlan(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("LoadPlan() error = %v", err)
+	}
+
+	report, err := reconciler.Apply(context.Background(), loaded, NoLimits)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if report.Succeeded != len(loaded.Operations) {
+		t.Errorf("Apply() succeeded = %v, want %v", report.Succeeded, len(loaded.Operations))
+	}
+
+	for _, call := range []string{
+		"POST /v1/key-cards",
+		"PATCH /v1/key-cards/0xa1",
+		"POST /v1/key-cards/0xb1/resume",
+		"POST /v1/key-cards/0xc1/suspend",
+		"POST /v1/key-cards/0xd1/suspend",
+	} {
+		if _, ok := calls.Load(call); !ok {
+			t.Errorf("Apply() expected request %s", call)
+		}
+	}
+}
+
+func TestReconciler_ApplyLimits(t *testing.T) {
+	server, cards, calls := setupReconcileTestServer()
+	defer server.Close()
+
+	reconciler, _ := NewReconciler(cards, WithOrphanAction(ActionDelete))
+	plan, _ := reconciler.Plan(context.Background(), testRoster)
+
+	_, err := reconciler.Apply(context.Background(), plan, Limits{MaxDeletePercent: 10})
+	if !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("Apply() error = %v, want ErrLimitExceeded", err)
+	}
+	if _, ok := calls.Load("POST /v1/key-cards/0xd1/delete"); ok {
+		t.Error("Apply() made changes despite exceeding limits")
+	}
+}
+
+func TestReconciler_PlanRejectsDuplicates(t *testing.T) {
+	server, cards, _ := setupReconcileTestServer()
+	defer server.Close()
+
+	reconciler, _ := NewReconciler(cards)
+	_, err := reconciler.Plan(context.Background(), []Cardholder{
+		{EmployeeID: "100", TemplateID: "0xd3adb00b5"},
+		{EmployeeID: "100", TemplateID: "0xd3adb00b5"},
+	})
+	if err == nil {
+		t.Error("Plan() expected error for duplicate cardholders")
+	}
+}
+
+func TestReconciler_ApplyDefaultLimits(t *testing.T) {
+	server, cards, calls := setupReconcileTestServer()
+	defer server.Close()
+
+	// Two of four cards would be suspended, above the default 25%
+	reconciler, _ := NewReconciler(cards)
+	plan, _ := reconciler.Plan(context.Background(), testRoster)
+
+	_, err := reconciler.Apply(context.Background(), plan, Limits{})
+	if !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("Apply() with zero limits error = %v, want ErrLimitExceeded", err)
+	}
+	if _, ok := calls.Load("POST /v1/key-cards/0xc1/suspend"); ok {
+		t.Error("Apply() made changes despite exceeding default limits")
+	}
+}
+
+func TestReconciler_ApplyOrdersCardOperations(t *testing.T) {
+	var mu sync.Mutex
+	var order []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		order = append(order, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		if r.Method == http.MethodPatch {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"message": "update failed"}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c, _ := client.NewClient("test-account", "test-secret", client.WithBaseURL(server.URL))
+	reconciler, _ := NewReconciler(services.NewAccessCardsService(c), WithConcurrency(8))
+	plan := &Plan{ActualCount: 1, Operations: []Operation{
+		{Action: ActionUpdate, CardID: "0xa1", Update: &models.UpdateParams{CardID: "0xa1", FullName: "Alice Smith"}},
+		{Action: ActionSuspend, CardID: "0xa1"},
+	}}
+
+	report, err := reconciler.Apply(context.Background(), plan, NoLimits)
+	if err == nil {
+		t.Fatal("Apply() expected error for failed update")
+	}
+	if report.Failed != 2 || !strings.Contains(report.Results[1].Error, "skipped") {
+		t.Errorf("Apply() results = %+v, want suspend skipped after failed update", report.Results)
+	}
+	if len(order) != 1 || order[0] != "PATCH /v1/key-cards/0xa1" {
+		t.Errorf("requests = %v, want only the update", order)
+	}
+}
@@ -0,0 +1,61 @@
+package renewal
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Access-Grid/accessgrid-go/models"
+)
+
+// Policy computes a card's new expiration date
+type Policy interface {
+	NextExpiration(card models.Card) (time.Time, error)
+}
+
+// PolicyFunc adapts a function to the Policy interface
+type PolicyFunc func(card models.Card) (time.Time, error)
+
+// NextExpiration implements Policy
+func (f PolicyFunc) NextExpiration(card models.Card) (time.Time, error) {
+	return f(card)
+}
+
+// FixedDate sets every card's expiration to the same date
+func FixedDate(date time.Time) Policy {
+	return PolicyFunc(func(models.Card) (time.Time, error) {
+		return date, nil
+	})
+}
+
+// ExtendByDays pushes each card's current expiration out by the given
+// number of days
+func ExtendByDays(days int) Policy {
+	return PolicyFunc(func(card models.Card) (time.Time, error) {
+		return card.ExpirationDate.AddDate(0, 0, days), nil
+	})
+}
+
+// metadataDateLayouts are the date formats accepted by FromMetadata
+var metadataDateLayouts = []string{time.RFC3339, "2006-01-02"}
+
+// FromMetadata sets each card's expiration to a date stored in its metadata,
+// such as the end of a contract. The value must be an RFC 3339 timestamp or
+// a YYYY-MM-DD date; cards without it are skipped.
+func FromMetadata(key string) Policy {
+	return PolicyFunc(func(card models.Card) (time.Time, error) {
+		value, ok := card.Metadata[key]
+		if !ok {
+			return time.Time{}, fmt.Errorf("metadata %q not set", key)
+		}
+		str, ok := value.(string)
+		if !ok {
+			return time.Time{}, fmt.Errorf("metadata %q is not a string", key)
+		}
+		for _, layout := range metadataDateLayouts {
+			if date, err := time.Parse(layout, str); err == nil {
+				return date, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("metadata %q has unrecognized date %q", key, str)
+	})
+}
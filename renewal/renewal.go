@@ -0,0 +1,243 @@
+// Package renewal extends the expiration date of cards nearing expiry in
+// bulk, with a preview step before any change is applied
+package renewal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Access-Grid/accessgrid-go/models"
+	"github.com/Access-Grid/accessgrid-go/services"
+)
+
+// DefaultConcurrency is the number of updates applied in parallel unless
+// configured otherwise
+const DefaultConcurrency = 4
+
+// Selector chooses which cards to renew. Window is required; other empty
+// fields match every card.
+type Selector struct {
+	// Window selects cards expiring between now and now+Window. It must be
+	// positive so that an unset selector never renews every card.
+	Window         time.Duration
+	TemplateID     string
+	Classification string
+	// Metadata selects cards whose metadata values equal these
+	Metadata map[string]string
+	// IncludeExpired also selects cards that have already expired
+	IncludeExpired bool
+}
+
+// Change is a planned expiration update for a single card
+type Change struct {
+	CardID        string    `json:"card_id"`
+	EmployeeID    string    `json:"employee_id"`
+	FullName      string    `json:"full_name"`
+	OldExpiration time.Time `json:"old_expiration"`
+	NewExpiration time.Time `json:"new_expiration"`
+}
+
+// Skip is a selected card the policy could not renew
+type Skip struct {
+	CardID string `json:"card_id"`
+	Reason string `json:"reason"`
+}
+
+// Plan lists the changes a renewal would make
+type Plan struct {
+	Changes []Change `json:"changes"`
+	Skipped []Skip   `json:"skipped,omitempty"`
+}
+
+// Write prints the plan as a human-readable table
+func (p *Plan) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "CARD\tEMPLOYEE\tNAME\tCURRENT\tNEW")
+	for _, c := range p.Changes {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", c.CardID, c.EmployeeID, c.FullName,
+			c.OldExpiration.Format(time.DateOnly), c.NewExpiration.Format(time.DateOnly))
+	}
+	for _, s := range p.Skipped {
+		fmt.Fprintf(tw, "%s\t\t\tskipped: %s\t\n", s.CardID, s.Reason)
+	}
+	return tw.Flush()
+}
+
+// Result is the outcome of applying a single change
+type Result struct {
+	Change
+	Error string `json:"error,omitempty"`
+}
+
+// Report summarizes an applied plan
+type Report struct {
+	Results   []Result `json:"results"`
+	Succeeded int      `json:"succeeded"`
+	Failed    int      `json:"failed"`
+}
+
+// Renewer previews and applies bulk expiration renewals
+type Renewer struct {
+	cards       *services.AccessCardsService
+	concurrency int
+	now         func() time.Time
+}
+
+// Option allows for customizing the renewer
+type Option func(*Renewer)
+
+// WithConcurrency sets how many updates are applied in parallel
+func WithConcurrency(n int) Option {
+	return func(r *Renewer) {
+		r.concurrency = n
+	}
+}
+
+// NewRenewer creates a new Renewer
+func NewRenewer(cards *services.AccessCardsService, options ...Option) (*Renewer, error) {
+	if cards == nil {
+		return nil, errors.New("access cards service is required")
+	}
+
+	renewer := &Renewer{
+		cards:       cards,
+		concurrency: DefaultConcurrency,
+		now:         time.Now,
+	}
+
+	// Apply any custom options
+	for _, option := range options {
+		option(renewer)
+	}
+
+	if renewer.concurrency < 1 {
+		renewer.concurrency = 1
+	}
+
+	return renewer, nil
+}
+
+// Preview finds the cards matching selector and computes their new
+// expiration dates without changing anything. Cards the policy fails on, or
+// whose new date would not extend their current one, are listed as skipped.
+func (r *Renewer) Preview(ctx context.Context, selector Selector, policy Policy) (*Plan, error) {
+	if policy == nil {
+		return nil, errors.New("renewal policy is required")
+	}
+	if selector.Window <= 0 {
+		return nil, errors.New("renewal window must be positive")
+	}
+
+	var params *models.ListKeysParams
+	if selector.TemplateID != "" {
+		params = &models.ListKeysParams{TemplateID: selector.TemplateID}
+	}
+	cards, err := r.cards.List(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("error finding cards to renew: %w", err)
+	}
+
+	now := r.now()
+	plan := &Plan{}
+	for _, card := range cards {
+		if !selector.matches(card, now) {
+			continue
+		}
+
+		next, err := policy.NextExpiration(card)
+		if err != nil {
+			plan.Skipped = append(plan.Skipped, Skip{CardID: card.ID, Reason: err.Error()})
+			continue
+		}
+		if !next.After(card.ExpirationDate) {
+			plan.Skipped = append(plan.Skipped, Skip{CardID: card.ID, Reason: "new expiration does not extend current expiration"})
+			continue
+		}
+
+		plan.Changes = append(plan.Changes, Change{
+			CardID:        card.ID,
+			EmployeeID:    card.EmployeeID,
+			FullName:      card.FullName,
+			OldExpiration: card.ExpirationDate,
+			NewExpiration: next,
+		})
+	}
+
+	sort.Slice(plan.Changes, func(i, j int) bool {
+		return plan.Changes[i].OldExpiration.Before(plan.Changes[j].OldExpiration)
+	})
+
+	return plan, nil
+}
+
+// Apply updates every card in the plan through AccessCardsService.Update.
+// All changes are attempted; the returned error joins any failures.
+func (r *Renewer) Apply(ctx context.Context, plan *Plan) (*Report, error) {
+	report := &Report{Results: make([]Result, len(plan.Changes))}
+	errs := make([]error, len(plan.Changes))
+
+	sem := make(chan struct{}, r.concurrency)
+	var wg sync.WaitGroup
+	for i, change := range plan.Changes {
+		wg.Add(1)
+		go func(i int, change Change) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			expiration := change.NewExpiration
+			_, err := r.cards.Update(ctx, models.UpdateParams{
+				CardID:         change.CardID,
+				ExpirationDate: &expiration,
+			})
+
+			report.Results[i] = Result{Change: change}
+			if err != nil {
+				report.Results[i].Error = err.Error()
+				errs[i] = fmt.Errorf("card %s: %w", change.CardID, err)
+			}
+		}(i, change)
+	}
+	wg.Wait()
+
+	for _, result := range report.Results {
+		if result.Error == "" {
+			report.Succeeded++
+		} else {
+			report.Failed++
+		}
+	}
+
+	return report, errors.Join(errs...)
+}
+
+func (s Selector) matches(card models.Card, now time.Time) bool {
+	if card.ExpirationDate.IsZero() || card.State == models.CardStateDeleted {
+		return false
+	}
+	if s.TemplateID != "" && card.CardTemplateID != s.TemplateID {
+		return false
+	}
+	if s.Classification != "" && card.Classification != s.Classification {
+		return false
+	}
+	for key, want := range s.Metadata {
+		value, ok := card.Metadata[key]
+		if !ok || fmt.Sprint(value) != want {
+			return false
+		}
+	}
+	if !s.IncludeExpired && card.ExpirationDate.Before(now) {
+		return false
+	}
+	if card.ExpirationDate.After(now.Add(s.Window)) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,159 @@
+package renewal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Access-Grid/accessgrid-go/client"
+	"github.com/Access-Grid/accessgrid-go/services"
+)
+
+func setupRenewalTestServer() (*httptest.Server, *services.AccessCardsService, *sync.Map) {
+	updates := &sync.Map{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.URL.Path == "/v1/key-cards" && r.Method == http.MethodGet:
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{
+				"keys": [
+					{
+						"id": "0xs00n",
+						"card_template_id": "0xd3adb00b5",
+						"classification": "contractor",
+						"state": "active",
+						"expiration_date": "2023-01-20T00:00:00Z",
+						"metadata": {"contract_end": "2023-06-30"}
+					},
+					{
+						"id": "0xl4t3r",
+						"card_template_id": "0xd3adb00b5",
+						"classification": "contractor",
+						"state": "active",
+						"expiration_date": "2023-12-31T00:00:00Z"
+					},
+					{
+						"id": "0xf4ll",
+						"card_template_id": "0xd3adb00b5",
+						"classification": "full_time",
+						"state": "active",
+						"expiration_date": "2023-01-15T00:00:00Z"
+					},
+					{
+						"id": "0xn0m3t4",
+						"card_template_id": "0xd3adb00b5",
+						"classification": "contractor",
+						"state": "active",
+						"expiration_date": "2023-01-10T00:00:00Z"
+					}
+				]
+			}`))
+		case r.Method == http.MethodPatch:
+			var body struct {
+				ExpirationDate time.Time `json:"expiration_date"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			updates.Store(r.URL.Path, body.ExpirationDate)
+			if strings.HasSuffix(r.URL.Path, "0xf4ll") {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"message": "update failed"}`))
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	c, _ := client.NewClient("test-account", "test-secret", client.WithBaseURL(server.URL))
+	return server, services.NewAccessCardsService(c), updates
+}
+
+func newTestRenewer(t *testing.T, cards *services.AccessCardsService) *Renewer {
+	renewer, err := NewRenewer(cards, WithConcurrency(2))
+	if err != nil {
+		t.Fatalf("NewRenewer() error = %v", err)
+	}
+	now, _ := time.Parse(time.RFC3339, "2023-01-01T00:00:00Z")
+	renewer.now = func() time.Time { return now }
+	return renewer
+}
+
+func TestRenewer_PreviewFromMetadata(t *testing.T) {
+	server, cards, _ := setupRenewalTestServer()
+	defer server.Close()
+
+	renewer := newTestRenewer(t, cards)
+	selector := Selector{Window: 30 * 24 * time.Hour, Classification: "contractor"}
+
+	plan, err := renewer.Preview(context.Background(), selector, FromMetadata("contract_end"))
+	if err != nil {
+		t.Fatalf("Preview() error = %v", err)
+	}
+
+	if len(plan.Changes) != 1 {
+		t.Fatalf("Preview() got %v changes, want %v", len(plan.Changes), 1)
+	}
+	want, _ := time.Parse(time.DateOnly, "2023-06-30")
+	if plan.Changes[0].CardID != "0xs00n" || !plan.Changes[0].NewExpiration.Equal(want) {
+		t.Errorf("Preview() change = %+v, want 0xs00n to %v", plan.Changes[0], want)
+	}
+	if len(plan.Skipped) != 1 || plan.Skipped[0].CardID != "0xn0m3t4" {
+		t.Errorf("Preview() skipped = %+v, want 0xn0m3t4", plan.Skipped)
+	}
+
+	var out bytes.Buffer
+	if err := plan.Write(&out); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "2023-06-30") {
+		t.Errorf("Write() output missing new date:\n%s", out.String())
+	}
+}
+
+func TestRenewer_Apply(t *testing.T) {
+	server, cards, updates := setupRenewalTestServer()
+	defer server.Close()
+
+	renewer := newTestRenewer(t, cards)
+	plan, err := renewer.Preview(context.Background(), Selector{Window: 30 * 24 * time.Hour}, ExtendByDays(90))
+	if err != nil {
+		t.Fatalf("Preview() error = %v", err)
+	}
+	if len(plan.Changes) != 3 {
+		t.Fatalf("Preview() got %v changes, want %v", len(plan.Changes), 3)
+	}
+
+	report, err := renewer.Apply(context.Background(), plan)
+	if err == nil {
+		t.Error("Apply() expected error for failed update")
+	}
+	if report.Succeeded != 2 || report.Failed != 1 {
+		t.Errorf("Apply() succeeded/failed = %v/%v, want 2/1", report.Succeeded, report.Failed)
+	}
+
+	got, ok := updates.Load("/v1/key-cards/0xs00n")
+	want, _ := time.Parse(time.RFC3339, "2023-04-20T00:00:00Z")
+	if !ok || !got.(time.Time).Equal(want) {
+		t.Errorf("Apply() sent expiration %v for 0xs00n, want %v", got, want)
+	}
+}
+
+func TestRenewer_PreviewRequiresWindow(t *testing.T) {
+	server, cards, _ := setupRenewalTestServer()
+	defer server.Close()
+
+	renewer := newTestRenewer(t, cards)
+	if _, err := renewer.Preview(context.Background(), Selector{Classification: "contractor"}, ExtendByDays(90)); err == nil {
+		t.Error("Preview() without a window expected error")
+	}
+}
@@ -0,0 +1,248 @@
+// Package replica mirrors an account's cards and templates into a local
+// Store so dashboards can query them without calling the API. After a full
+// initial sync, the replica stays current using template event logs, and a
+// periodic full checksum pass repairs any drift the logs miss.
+package replica
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Access-Grid/accessgrid-go/client"
+	"github.com/Access-Grid/accessgrid-go/models"
+	"github.com/Access-Grid/accessgrid-go/services"
+)
+
+// Replica keeps a Store in sync with the API
+type Replica struct {
+	cards   *services.AccessCardsService
+	console *services.ConsoleService
+	store   *Store
+	now     func() time.Time
+}
+
+// NewReplica creates a new Replica. If store is nil an empty Store is used;
+// pass a restored Store to resume from a snapshot without a full sync.
+func NewReplica(cards *services.AccessCardsService, console *services.ConsoleService, store *Store) (*Replica, error) {
+	if cards == nil {
+		return nil, errors.New("access cards service is required")
+	}
+	if console == nil {
+		return nil, errors.New("console service is required")
+	}
+	if store == nil {
+		store = NewStore()
+	}
+	return &Replica{cards: cards, console: console, store: store, now: time.Now}, nil
+}
+
+// Store returns the local store answering queries
+func (r *Replica) Store() *Store {
+	return r.store
+}
+
+// Sync replaces the store's contents with a full copy of every template and
+// card
+func (r *Replica) Sync(ctx context.Context) error {
+	syncedAt := r.now()
+
+	templates, err := r.console.ListTemplates(ctx)
+	if err != nil {
+		return fmt.Errorf("error syncing templates: %w", err)
+	}
+	cards, err := r.cards.List(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error syncing cards: %w", err)
+	}
+
+	r.store.replaceTemplates(templates)
+	r.store.replaceCards(cards, syncedAt)
+	eventsSeen := make(map[string]time.Time, len(templates))
+	for _, template := range templates {
+		eventsSeen[template.ID] = syncedAt
+	}
+	r.store.advanceCursor(syncedAt, eventsSeen)
+	return nil
+}
+
+// RefreshReport summarizes an incremental refresh
+type RefreshReport struct {
+	EventsRead   int `json:"events_read"`
+	CardsUpdated int `json:"cards_updated"`
+	CardsRemoved int `json:"cards_removed"`
+}
+
+// Refresh brings the store up to date incrementally by re-reading the cards
+// referenced by events since the last refresh. Changes that produce no event
+// are not seen until the next Verify. If the store has never been synced,
+// Refresh performs a full Sync instead. The cursor only moves once every
+// touched card has been re-read, so a failed Refresh is retried from the
+// same point.
+func (r *Replica) Refresh(ctx context.Context) (*RefreshReport, error) {
+	cursor := r.store.Cursor()
+	if cursor.SyncedAt.IsZero() {
+		return &RefreshReport{}, r.Sync(ctx)
+	}
+
+	startedAt := r.now()
+	report := &RefreshReport{}
+
+	templates, err := r.console.ListTemplates(ctx)
+	if err != nil {
+		return report, fmt.Errorf("error refreshing templates: %w", err)
+	}
+	r.store.replaceTemplates(templates)
+
+	// Collect every card touched by an event since the last refresh
+	touched := make(map[string]bool)
+	eventsSeen := make(map[string]time.Time, len(templates))
+	for _, template := range templates {
+		since, ok := cursor.EventsSeen[template.ID]
+		if !ok {
+			since = cursor.SyncedAt
+		}
+
+		events, err := r.console.EventLog(ctx, template.ID, models.EventLogFilters{StartDate: &since})
+		if err != nil {
+			return report, fmt.Errorf("error reading events for template %s: %w", template.ID, err)
+		}
+
+		latest := since
+		for _, event := range events {
+			if event.Timestamp.Before(since) {
+				continue
+			}
+			report.EventsRead++
+			if event.CardID != "" {
+				touched[event.CardID] = true
+			}
+			if event.Timestamp.After(latest) {
+				latest = event.Timestamp
+			}
+		}
+		eventsSeen[template.ID] = latest
+	}
+
+	for cardID := range touched {
+		pass, err := r.cards.Get(ctx, cardID)
+		if client.IsNotFound(err) {
+			r.store.deleteCard(cardID)
+			report.CardsRemoved++
+			continue
+		}
+		if err != nil {
+			return report, fmt.Errorf("error refreshing card %s: %w", cardID, err)
+		}
+		r.store.putCards(pass.GetCards())
+		report.CardsUpdated++
+	}
+
+	r.store.advanceCursor(startedAt, eventsSeen)
+	return report, nil
+}
+
+// VerifyReport describes the outcome of a checksum pass
+type VerifyReport struct {
+	InSync bool `json:"in_sync"`
+	// Missing lists cards present remotely but not locally
+	Missing []string `json:"missing,omitempty"`
+	// Extra lists cards present locally but not remotely
+	Extra []string `json:"extra,omitempty"`
+	// Stale lists cards whose local state or update time differs
+	Stale []string `json:"stale,omitempty"`
+}
+
+// Verify compares a checksum of the local cards with a fresh listing and,
+// if they differ, repairs the store from that listing. It is a full scan of
+// every card on the account, as costly as Sync, so run it much less often
+// than Refresh.
+func (r *Replica) Verify(ctx context.Context) (*VerifyReport, error) {
+	syncedAt := r.now()
+	remote, err := r.cards.List(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error verifying replica: %w", err)
+	}
+
+	if checksumCards(remote) == r.store.checksum() {
+		return &VerifyReport{InSync: true}, nil
+	}
+
+	report := &VerifyReport{}
+	seen := make(map[string]bool, len(remote))
+	for _, card := range remote {
+		if card.State == models.CardStateDeleted {
+			continue
+		}
+		seen[card.ID] = true
+		local, ok := r.store.Card(card.ID)
+		switch {
+		case !ok:
+			report.Missing = append(report.Missing, card.ID)
+		case local.State != card.State || !local.UpdatedAt.Equal(card.UpdatedAt):
+			report.Stale = append(report.Stale, card.ID)
+		}
+	}
+	for _, card := range r.store.Cards(Query{}) {
+		if !seen[card.ID] {
+			report.Extra = append(report.Extra, card.ID)
+		}
+	}
+
+	r.store.replaceCards(remote, syncedAt)
+	return report, nil
+}
+
+// RunOptions configures Run
+type RunOptions struct {
+	// RefreshInterval is how often Refresh runs. Defaults to one minute.
+	RefreshInterval time.Duration
+	// VerifyInterval is how often Verify runs. Zero disables verification.
+	VerifyInterval time.Duration
+	// OnError is called with errors from background passes. Run keeps
+	// going after an error.
+	OnError func(error)
+}
+
+// Run keeps the store current until ctx is cancelled, performing a full
+// Sync first if the store is empty
+func (r *Replica) Run(ctx context.Context, opts RunOptions) error {
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = time.Minute
+	}
+	report := func(err error) {
+		if err != nil && opts.OnError != nil {
+			opts.OnError(err)
+		}
+	}
+
+	if r.store.Cursor().SyncedAt.IsZero() {
+		if err := r.Sync(ctx); err != nil {
+			return err
+		}
+	}
+
+	refresh := time.NewTicker(opts.RefreshInterval)
+	defer refresh.Stop()
+
+	var verify <-chan time.Time
+	if opts.VerifyInterval > 0 {
+		ticker := time.NewTicker(opts.VerifyInterval)
+		defer ticker.Stop()
+		verify = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-refresh.C:
+			_, err := r.Refresh(ctx)
+			report(err)
+		case <-verify:
+			_, err := r.Verify(ctx)
+			report(err)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package replica
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Access-Grid/accessgrid-go/client"
+	"github.com/Access-Grid/accessgrid-go/models"
+	"github.com/Access-Grid/accessgrid-go/services"
+)
+
+// fakeAccount is a mutable stand-in for the API
+type fakeAccount struct {
+	mu     sync.Mutex
+	cards  map[string]models.Card
+	events []models.Event
+	// failGet makes reads of this card fail
+	failGet string
+}
+
+func (f *fakeAccount) handler(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+
+	switch {
+	case r.URL.Path == "/v1/console/card-templates":
+		json.NewEncoder(w).Encode([]models.Template{{ID: "0xd3adb00b5", Name: "Employee NFC key"}})
+	case r.URL.Path == "/v1/console/card-templates/0xd3adb00b5/logs":
+		json.NewEncoder(w).Encode(f.events)
+	case r.URL.Path == "/v1/key-cards":
+		var keys []models.Card
+		for _, card := range f.cards {
+			keys = append(keys, card)
+		}
+		json.NewEncoder(w).Encode(map[string][]models.Card{"keys": keys})
+	case r.URL.Path == "/v1/key-cards/"+f.failGet:
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"message": "unavailable"}`))
+	case strings.HasPrefix(r.URL.Path, "/v1/key-cards/"):
+		card, ok := f.cards[strings.TrimPrefix(r.URL.Path, "/v1/key-cards/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "not found"}`))
+			return
+		}
+		json.NewEncoder(w).Encode(card)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func setupReplicaTest(t *testing.T) (*httptest.Server, *fakeAccount, *Replica, *time.Time) {
+	base, _ := time.Parse(time.RFC3339, "2023-01-01T00:00:00Z")
+	account := &fakeAccount{cards: map[string]models.Card{
+		"0xa1": {ID: "0xa1", CardTemplateID: "0xd3adb00b5", EmployeeID: "100", State: "active", ExpirationDate: base.AddDate(0, 1, 0), UpdatedAt: base},
+		"0xb1": {ID: "0xb1", CardTemplateID: "0xd3adb00b5", EmployeeID: "200", State: "active", ExpirationDate: base.AddDate(1, 0, 0), UpdatedAt: base},
+	}}
+	server := httptest.NewServer(http.HandlerFunc(account.handler))
+
+	c, _ := client.NewClient("test-account", "test-secret", client.WithBaseURL(server.URL))
+	replica, err := NewReplica(services.NewAccessCardsService(c), services.NewConsoleService(c), nil)
+	if err != nil {
+		t.Fatalf("NewReplica() error = %v", err)
+	}
+
+	now := base.Add(time.Hour)
+	replica.now = func() time.Time { return now }
+	return server, account, replica, &now
+}
+
+func TestReplica_SyncAndQuery(t *testing.T) {
+	server, _, replica, now := setupReplicaTest(t)
+	defer server.Close()
+
+	if err := replica.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	store := replica.Store()
+	if got := len(store.Cards(Query{State: "active"})); got != 2 {
+		t.Errorf("Cards(active) got %v, want %v", got, 2)
+	}
+	expiring := store.Cards(Query{ExpiresBefore: now.AddDate(0, 2, 0)})
+	if len(expiring) != 1 || expiring[0].ID != "0xa1" {
+		t.Errorf("Cards(ExpiresBefore) = %+v, want 0xa1", expiring)
+	}
+	if _, ok := store.Template("0xd3adb00b5"); !ok {
+		t.Error("Template() expected synced template")
+	}
+}
+
+func TestReplica_Refresh(t *testing.T) {
+	server, account, replica, now := setupReplicaTest(t)
+	defer server.Close()
+
+	ctx := context.Background()
+	if err := replica.Sync(ctx); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	// One card changes, one is added and one is deleted, each with an event
+	changedAt := now.Add(10 * time.Minute)
+	account.mu.Lock()
+	card := account.cards["0xa1"]
+	card.State = "suspended"
+	card.UpdatedAt = changedAt
+	account.cards["0xa1"] = card
+	account.cards["0xc1"] = models.Card{ID: "0xc1", CardTemplateID: "0xd3adb00b5", State: "active", UpdatedAt: changedAt}
+	delete(account.cards, "0xb1")
+	account.events = []models.Event{
+		{ID: "evt_1", Type: "suspend", CardID: "0xa1", Timestamp: changedAt},
+		{ID: "evt_2", Type: "delete", CardID: "0xb1", Timestamp: changedAt},
+		{ID: "evt_3", Type: "provision", CardID: "0xc1", Timestamp: changedAt},
+	}
+	account.mu.Unlock()
+	*now = changedAt.Add(time.Minute)
+
+	report, err := replica.Refresh(ctx)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	if report.EventsRead != 3 || report.CardsRemoved != 1 || report.CardsUpdated != 2 {
+		t.Errorf("Refresh() report = %+v, want 3 events, 2 updated, 1 removed", report)
+	}
+	store := replica.Store()
+	if card, _ := store.Card("0xa1"); card.State != "suspended" {
+		t.Errorf("Refresh() 0xa1 state = %v, want suspended", card.State)
+	}
+	if _, ok := store.Card("0xb1"); ok {
+		t.Error("Refresh() expected 0xb1 to be removed")
+	}
+	if _, ok := store.Card("0xc1"); !ok {
+		t.Error("Refresh() expected 0xc1 to be added")
+	}
+}
+
+func TestReplica_RefreshRetriesAfterFailure(t *testing.T) {
+	server, account, replica, now := setupReplicaTest(t)
+	defer server.Close()
+
+	ctx := context.Background()
+	if err := replica.Sync(ctx); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	changedAt := now.Add(10 * time.Minute)
+	account.mu.Lock()
+	card := account.cards["0xa1"]
+	card.State = "suspended"
+	card.UpdatedAt = changedAt
+	account.cards["0xa1"] = card
+	account.events = []models.Event{
+		{ID: "evt_1", Type: "suspend", CardID: "0xa1", Timestamp: changedAt},
+		{ID: "evt_2", Type: "update", CardID: "0xb1", Timestamp: changedAt.Add(time.Minute)},
+	}
+	account.failGet = "0xa1"
+	account.mu.Unlock()
+	*now = changedAt.Add(2 * time.Minute)
+
+	if _, err := replica.Refresh(ctx); err == nil {
+		t.Fatal("Refresh() expected error")
+	}
+
+	// The cursor did not move, so the next refresh sees the event again
+	account.mu.Lock()
+	account.failGet = ""
+	account.mu.Unlock()
+	report, err := replica.Refresh(ctx)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if report.CardsUpdated != 2 {
+		t.Errorf("Refresh() report = %+v, want 2 updated", report)
+	}
+	if card, _ := replica.Store().Card("0xa1"); card.State != "suspended" {
+		t.Errorf("Refresh() 0xa1 state = %v, want suspended", card.State)
+	}
+}
+
+func TestReplica_VerifyRepairsDrift(t *testing.T) {
+	server, account, replica, _ := setupReplicaTest(t)
+	defer server.Close()
+
+	ctx := context.Background()
+	if err := replica.Sync(ctx); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	report, err := replica.Verify(ctx)
+	if err != nil || !report.InSync {
+		t.Fatalf("Verify() = %+v, %v, want in sync", report, err)
+	}
+
+	// Remove a card without any event or update time to signal it
+	account.mu.Lock()
+	delete(account.cards, "0xb1")
+	account.mu.Unlock()
+
+	report, err = replica.Verify(ctx)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if report.InSync || len(report.Extra) != 1 || report.Extra[0] != "0xb1" {
+		t.Errorf("Verify() report = %+v, want 0xb1 extra", report)
+	}
+	if _, ok := replica.Store().Card("0xb1"); ok {
+		t.Error("Verify() expected 0xb1 to be removed from store")
+	}
+}
+
+func TestStore_SnapshotRestore(t *testing.T) {
+	server, _, replica, _ := setupReplicaTest(t)
+	defer server.Close()
+
+	if err := replica.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := replica.Store().Snapshot(&buf); err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+
+	restored := NewStore()
+	if err := restored.Restore(&buf); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if got := len(restored.Cards(Query{})); got != 2 {
+		t.Errorf("Restore() got %v cards, want %v", got, 2)
+	}
+	if restored.Cursor().SyncedAt.IsZero() {
+		t.Error("Restore() expected cursor to be restored")
+	}
+}
@@ -0,0 +1,271 @@
+package replica
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Access-Grid/accessgrid-go/models"
+)
+
+// Query selects cards from the store. Empty fields match every card.
+type Query struct {
+	State      string
+	EmployeeID string
+	TemplateID string
+	// ExpiresBefore selects cards expiring before this time
+	ExpiresBefore time.Time
+	// ExpiresAfter selects cards expiring after this time
+	ExpiresAfter time.Time
+}
+
+func (q Query) matches(card models.Card) bool {
+	if q.State != "" && card.State != q.State {
+		return false
+	}
+	if q.EmployeeID != "" && card.EmployeeID != q.EmployeeID {
+		return false
+	}
+	if q.TemplateID != "" && card.CardTemplateID != q.TemplateID {
+		return false
+	}
+	if !q.ExpiresBefore.IsZero() && !card.ExpirationDate.Before(q.ExpiresBefore) {
+		return false
+	}
+	if !q.ExpiresAfter.IsZero() && !card.ExpirationDate.After(q.ExpiresAfter) {
+		return false
+	}
+	return true
+}
+
+// Cursor records how far the store has been synced
+type Cursor struct {
+	// SyncedAt is when cards were last fetched from the API
+	SyncedAt time.Time `json:"synced_at"`
+	// EventsSeen is the timestamp of the latest event read per template
+	EventsSeen map[string]time.Time `json:"events_seen"`
+}
+
+// Store is an in-memory copy of an account's cards and templates. It is
+// safe for concurrent use and can be persisted with Snapshot and Restore.
+type Store struct {
+	mu        sync.RWMutex
+	cards     map[string]models.Card
+	templates map[string]models.Template
+	cursor    Cursor
+}
+
+// NewStore creates an empty Store
+func NewStore() *Store {
+	return &Store{
+		cards:     make(map[string]models.Card),
+		templates: make(map[string]models.Template),
+		cursor:    Cursor{EventsSeen: make(map[string]time.Time)},
+	}
+}
+
+// Card returns a card by ID
+func (s *Store) Card(id string) (models.Card, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	card, ok := s.cards[id]
+	return card, ok
+}
+
+// Cards returns the cards matching q ordered by ID
+func (s *Store) Cards(q Query) []models.Card {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var cards []models.Card
+	for _, card := range s.cards {
+		if q.matches(card) {
+			cards = append(cards, card)
+		}
+	}
+	sort.Slice(cards, func(i, j int) bool { return cards[i].ID < cards[j].ID })
+	return cards
+}
+
+// Template returns a template by ID
+func (s *Store) Template(id string) (models.Template, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	template, ok := s.templates[id]
+	return template, ok
+}
+
+// Templates returns every template ordered by ID
+func (s *Store) Templates() []models.Template {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	templates := make([]models.Template, 0, len(s.templates))
+	for _, template := range s.templates {
+		templates = append(templates, template)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].ID < templates[j].ID })
+	return templates
+}
+
+// Cursor returns a copy of the sync cursor
+func (s *Store) Cursor() Cursor {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	cursor := Cursor{SyncedAt: s.cursor.SyncedAt, EventsSeen: make(map[string]time.Time, len(s.cursor.EventsSeen))}
+	for k, v := range s.cursor.EventsSeen {
+		cursor.EventsSeen[k] = v
+	}
+	return cursor
+}
+
+func (s *Store) putCards(cards []models.Card) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, card := range cards {
+		if card.State == models.CardStateDeleted {
+			delete(s.cards, card.ID)
+			continue
+		}
+		s.cards[card.ID] = card
+	}
+}
+
+func (s *Store) deleteCard(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.cards, id)
+}
+
+// replaceCards swaps in a complete set of cards
+func (s *Store) replaceCards(cards []models.Card, syncedAt time.Time) {
+	fresh := make(map[string]models.Card, len(cards))
+	for _, card := range cards {
+		if card.State != models.CardStateDeleted {
+			fresh[card.ID] = card
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cards = fresh
+	s.cursor.SyncedAt = syncedAt
+}
+
+// replaceTemplates swaps in a complete set of templates
+func (s *Store) replaceTemplates(templates []models.Template) {
+	fresh := make(map[string]models.Template, len(templates))
+	for _, template := range templates {
+		fresh[template.ID] = template
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.templates = fresh
+	for id := range s.cursor.EventsSeen {
+		if _, ok := fresh[id]; !ok {
+			delete(s.cursor.EventsSeen, id)
+		}
+	}
+}
+
+// advanceCursor records a completed sync or refresh and the latest event
+// seen for each template
+func (s *Store) advanceCursor(syncedAt time.Time, eventsSeen map[string]time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cursor.SyncedAt = syncedAt
+	for templateID, t := range eventsSeen {
+		s.cursor.EventsSeen[templateID] = t
+	}
+}
+
+// checksum hashes the ID, state and update time of every card in the store
+func (s *Store) checksum() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	cards := make([]models.Card, 0, len(s.cards))
+	for _, card := range s.cards {
+		cards = append(cards, card)
+	}
+	return checksumCards(cards)
+}
+
+func checksumCards(cards []models.Card) string {
+	lines := make([]string, 0, len(cards))
+	for _, card := range cards {
+		if card.State == models.CardStateDeleted {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s|%s|%s", card.ID, card.State, card.UpdatedAt.UTC().Format(time.RFC3339Nano)))
+	}
+	sort.Strings(lines)
+
+	h := sha256.New()
+	for _, line := range lines {
+		h.Write([]byte(line))
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+type snapshot struct {
+	Cards     []models.Card     `json:"cards"`
+	Templates []models.Template `json:"templates"`
+	Cursor    Cursor            `json:"cursor"`
+}
+
+// Snapshot writes the store's contents as JSON
+func (s *Store) Snapshot(w io.Writer) error {
+	snap := snapshot{
+		Cards:     s.Cards(Query{}),
+		Templates: s.Templates(),
+		Cursor:    s.Cursor(),
+	}
+	if err := json.NewEncoder(w).Encode(snap); err != nil {
+		return fmt.Errorf("error writing replica snapshot: %w", err)
+	}
+	return nil
+}
+
+// Restore replaces the store's contents with a snapshot
+func (s *Store) Restore(r io.Reader) error {
+	var snap snapshot
+	if err := json.NewDecoder(r).Decode(&snap); err != nil {
+		return fmt.Errorf("error reading replica snapshot: %w", err)
+	}
+
+	cards := make(map[string]models.Card, len(snap.Cards))
+	for _, card := range snap.Cards {
+		cards[card.ID] = card
+	}
+	templates := make(map[string]models.Template, len(snap.Templates))
+	for _, template := range snap.Templates {
+		templates[template.ID] = template
+	}
+	if snap.Cursor.EventsSeen == nil {
+		snap.Cursor.EventsSeen = make(map[string]time.Time)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cards = cards
+	s.templates = templates
+	s.cursor = snap.Cursor
+	return nil
+}
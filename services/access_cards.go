@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -13,7 +14,8 @@ import (
 
 // AccessCardsService handles operations related to NFC cards
 type AccessCardsService struct {
-	client *client.Client
+	client      *client.Client
+	lookupCache lookupCache
 }
 
 // NewAccessCardsService creates a new AccessCardsService
@@ -21,14 +23,23 @@ func NewAccessCardsService(client *client.Client) *AccessCardsService {
 	return &AccessCardsService{client: client}
 }
 
-// Provision creates a new NFC key/card
+// Provision creates a new NFC key/card. Exactly one of CardTemplateID and
+// CardTemplatePairID must be set. Provisioning against a template pair
+// returns a UnifiedAccessPass; see ProvisionPair.
 func (s *AccessCardsService) Provision(ctx context.Context, params models.ProvisionParams) (models.Union, error) {
+	switch {
+	case params.CardTemplateID == "" && params.CardTemplatePairID == "":
+		return nil, errors.New("card template ID or card template pair ID is required")
+	case params.CardTemplateID != "" && params.CardTemplatePairID != "":
+		return nil, errors.New("set only one of card template ID and card template pair ID")
+	}
+
 	var raw json.RawMessage
 	err := s.client.Request(ctx, http.MethodPost, "/v1/key-cards", params, &raw)
 	if err != nil {
 		return nil, fmt.Errorf("error provisioning card: %w", err)
 	}
-	return parseUnionResponse(raw)
+	return models.ParseUnion(raw)
 }
 
 // Get retrieves a specific NFC key/card by ID
@@ -39,7 +50,7 @@ func (s *AccessCardsService) Get(ctx context.Context, cardID string) (models.Uni
 	if err != nil {
 		return nil, fmt.Errorf("error getting card: %w", err)
 	}
-	return parseUnionResponse(raw)
+	return models.ParseUnion(raw)
 }
 
 // Update updates an existing NFC key/card
@@ -95,6 +106,30 @@ func (s *AccessCardsService) Unlink(ctx context.Context, cardID string) error {
 	return nil
 }
 
+// ListDevices retrieves the devices a card is installed on
+func (s *AccessCardsService) ListDevices(ctx context.Context, cardID string) ([]models.Device, error) {
+	var response struct {
+		Devices []models.Device `json:"devices"`
+	}
+	path := fmt.Sprintf("/v1/key-cards/%s/devices", url.PathEscape(cardID))
+	err := s.client.Request(ctx, http.MethodGet, path, nil, &response)
+	if err != nil {
+		return nil, fmt.Errorf("error listing devices: %w", err)
+	}
+	return response.Devices, nil
+}
+
+// UnlinkDevice unlinks a single device from a card, leaving any other
+// devices the card is installed on untouched
+func (s *AccessCardsService) UnlinkDevice(ctx context.Context, cardID, deviceID string) error {
+	path := fmt.Sprintf("/v1/key-cards/%s/devices/%s/unlink", url.PathEscape(cardID), url.PathEscape(deviceID))
+	err := s.client.Request(ctx, http.MethodPost, path, map[string]string{}, nil)
+	if err != nil {
+		return fmt.Errorf("error unlinking device: %w", err)
+	}
+	return nil
+}
+
 // Delete deletes a card
 func (s *AccessCardsService) Delete(ctx context.Context, cardID string) error {
 	path := fmt.Sprintf("/v1/key-cards/%s/delete", url.PathEscape(cardID))
@@ -104,26 +139,3 @@ func (s *AccessCardsService) Delete(ctx context.Context, cardID string) error {
 	}
 	return nil
 }
-
-func parseUnionResponse(raw json.RawMessage) (models.Union, error) {
-	var check struct {
-		Details []json.RawMessage `json:"details"`
-	}
-	if err := json.Unmarshal(raw, &check); err != nil {
-		return nil, fmt.Errorf("error parsing response: %w", err)
-	}
-
-	if len(check.Details) > 0 {
-		var uap models.UnifiedAccessPass
-		if err := json.Unmarshal(raw, &uap); err != nil {
-			return nil, fmt.Errorf("error parsing unified access pass: %w", err)
-		}
-		return &uap, nil
-	}
-
-	var card models.Card
-	if err := json.Unmarshal(raw, &card); err != nil {
-		return nil, fmt.Errorf("error parsing card: %w", err)
-	}
-	return &card, nil
-}
@@ -62,6 +62,27 @@ func setupAccessCardsTestServer() (*httptest.Server, *AccessCardsService) {
 		case "/v1/key-cards/0xc4rd1d/delete":
 			// Delete
 			w.Write([]byte(`{}`))
+		case "/v1/key-cards/0xc4rd1d/devices":
+			// List Devices
+			w.Write([]byte(`{
+				"devices": [
+					{
+						"id": "dev_iphone",
+						"platform": "apple",
+						"device_type": "iphone",
+						"status": "active"
+					},
+					{
+						"id": "dev_watch",
+						"platform": "apple",
+						"device_type": "watch",
+						"status": "active"
+					}
+				]
+			}`))
+		case "/v1/key-cards/0xc4rd1d/devices/dev_watch/unlink":
+			// Unlink Device
+			w.Write([]byte(`{}`))
 		}
 	}))
 
@@ -193,3 +214,29 @@ func TestAccessCardsService_CardStateOperations(t *testing.T) {
 		t.Errorf("Delete() error = %v", err)
 	}
 }
+
+func TestAccessCardsService_Devices(t *testing.T) {
+	server, service := setupAccessCardsTestServer()
+	defer server.Close()
+
+	ctx := context.Background()
+	devices, err := service.ListDevices(ctx, "0xc4rd1d")
+	if err != nil {
+		t.Fatalf("ListDevices() error = %v", err)
+	}
+
+	if len(devices) != 2 {
+		t.Fatalf("ListDevices() got %v devices, want %v", len(devices), 2)
+	}
+	if devices[1].DeviceType != models.DeviceTypeWatch {
+		t.Errorf("ListDevices() devices[1].DeviceType = %v, want %v", devices[1].DeviceType, models.DeviceTypeWatch)
+	}
+	if devices[0].Platform != models.DevicePlatformApple {
+		t.Errorf("ListDevices() devices[0].Platform = %v, want %v", devices[0].Platform, models.DevicePlatformApple)
+	}
+
+	err = service.UnlinkDevice(ctx, "0xc4rd1d", "dev_watch")
+	if err != nil {
+		t.Errorf("UnlinkDevice() error = %v", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/Access-Grid/accessgrid-go/client"
@@ -13,7 +14,8 @@ import (
 
 // ConsoleService handles operations related to the enterprise console
 type ConsoleService struct {
-	client *client.Client
+	client   *client.Client
+	versions templateVersioning
 }
 
 // NewConsoleService creates a new ConsoleService
@@ -21,8 +23,14 @@ func NewConsoleService(client *client.Client) *ConsoleService {
 	return &ConsoleService{client: client}
 }
 
-// CreateTemplate creates a new card template
+// CreateTemplate creates a new card template. The params are checked with
+// Validate first, and a *models.ValidationError is returned without making
+// a request if they are invalid.
 func (s *ConsoleService) CreateTemplate(ctx context.Context, params models.CreateTemplateParams) (*models.Template, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
 	var template models.Template
 	err := s.client.Request(ctx, http.MethodPost, "/v1/console/card-templates", params, &template)
 	if err != nil {
@@ -31,14 +39,23 @@ func (s *ConsoleService) CreateTemplate(ctx context.Context, params models.Creat
 	return &template, nil
 }
 
-// UpdateTemplate updates an existing card template
+// UpdateTemplate updates an existing card template. The params are checked
+// with Validate first, as for CreateTemplate. If a version store is set,
+// the template as it was before the update is saved as a new version once
+// the update succeeds; see SetTemplateVersionStore.
 func (s *ConsoleService) UpdateTemplate(ctx context.Context, params models.UpdateTemplateParams) (*models.Template, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	snapshot := s.readSnapshot(ctx, params.CardTemplateID)
+
 	var template models.Template
 	path := fmt.Sprintf("/v1/console/card-templates/%s", url.PathEscape(params.CardTemplateID))
 	err := s.client.Request(ctx, http.MethodPut, path, params, &template)
 	if err != nil {
 		return nil, fmt.Errorf("error updating template: %w", err)
 	}
+	snapshot.save(ctx)
 	return &template, nil
 }
 
@@ -63,7 +80,9 @@ func (s *ConsoleService) ListTemplates(ctx context.Context) ([]models.Template,
 	return templates, nil
 }
 
-// DeleteTemplate deletes a card template
+// DeleteTemplate deletes a card template immediately, regardless of any
+// cards issued from it. Use SafeDeleteTemplate to check for active cards
+// first.
 func (s *ConsoleService) DeleteTemplate(ctx context.Context, templateID string) error {
 	path := fmt.Sprintf("/v1/console/card-templates/%s", url.PathEscape(templateID))
 	err := s.client.Request(ctx, http.MethodDelete, path, nil, nil)
@@ -73,31 +92,18 @@ func (s *ConsoleService) DeleteTemplate(ctx context.Context, templateID string)
 	return nil
 }
 
-// EventLog retrieves event logs for a specific template
+// EventLog retrieves event logs for a specific template. Without Page and
+// PerPage the API returns every matching event; use EventLogIter to iterate
+// over large logs.
 func (s *ConsoleService) EventLog(ctx context.Context, templateID string, filters models.EventLogFilters) ([]models.Event, error) {
 	var events []models.Event
 
-	// Build query parameters
-	query := url.Values{}
-	if filters.Device != "" {
-		query.Add("device", filters.Device)
-	}
-	if filters.StartDate != nil {
-		query.Add("start_date", filters.StartDate.Format(time.RFC3339))
-	}
-	if filters.EndDate != nil {
-		query.Add("end_date", filters.EndDate.Format(time.RFC3339))
-	}
-	if filters.EventType != "" {
-		query.Add("event_type", filters.EventType)
-	}
-
 	// Build the URL properly using url.URL
 	u := url.URL{
 		Path: fmt.Sprintf("/v1/console/card-templates/%s/logs", url.PathEscape(templateID)),
 	}
 
-	if len(query) > 0 {
+	if query := eventLogQuery(filters); len(query) > 0 {
 		u.RawQuery = query.Encode()
 	}
 
@@ -110,3 +116,43 @@ func (s *ConsoleService) EventLog(ctx context.Context, templateID string, filter
 
 	return events, nil
 }
+
+// eventLogQuery builds the query parameters for an event log request.
+// Multi-valued filters repeat their parameter.
+func eventLogQuery(filters models.EventLogFilters) url.Values {
+	query := url.Values{}
+	if filters.Device != "" {
+		query.Add("device", filters.Device)
+	}
+	for _, device := range filters.Devices {
+		query.Add("device", device)
+	}
+	if filters.StartDate != nil {
+		query.Add("start_date", filters.StartDate.Format(time.RFC3339))
+	}
+	if filters.EndDate != nil {
+		query.Add("end_date", filters.EndDate.Format(time.RFC3339))
+	}
+	if filters.EventType != "" {
+		query.Add("event_type", string(filters.EventType))
+	}
+	for _, eventType := range filters.EventTypes {
+		query.Add("event_type", string(eventType))
+	}
+	if filters.CardID != "" {
+		query.Add("card_id", filters.CardID)
+	}
+	if filters.UserID != "" {
+		query.Add("user_id", filters.UserID)
+	}
+	if filters.Sort != "" {
+		query.Add("sort", string(filters.Sort))
+	}
+	if filters.Page > 0 {
+		query.Add("page", strconv.Itoa(filters.Page))
+	}
+	if filters.PerPage > 0 {
+		query.Add("per_page", strconv.Itoa(filters.PerPage))
+	}
+	return query
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -163,6 +164,32 @@ func TestConsoleService_UpdateTemplate(t *testing.T) {
 	}
 }
 
+func TestConsoleService_TemplateValidation(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c, _ := client.NewClient("test-account", "test-secret", client.WithBaseURL(server.URL))
+	service := NewConsoleService(c)
+	ctx := context.Background()
+
+	var verr *models.ValidationError
+	_, err := service.CreateTemplate(ctx, models.CreateTemplateParams{Name: "Employee NFC key", Platform: "kiosk"})
+	if !errors.As(err, &verr) || !verr.HasField("platform") {
+		t.Errorf("CreateTemplate() error = %v, want platform validation error", err)
+	}
+	_, err = service.UpdateTemplate(ctx, models.UpdateTemplateParams{CardTemplateID: "0xd3adb00b5", WatchCount: -1})
+	if !errors.As(err, &verr) || !verr.HasField("watch_count") {
+		t.Errorf("UpdateTemplate() error = %v, want watch_count validation error", err)
+	}
+	if requests != 0 {
+		t.Errorf("made %d requests, want none for invalid params", requests)
+	}
+}
+
 func TestConsoleService_ReadTemplate(t *testing.T) {
 	server, service := setupConsoleTestServer()
 	defer server.Close()
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"github.com/Access-Grid/accessgrid-go/models"
+)
+
+// defaultEventLogPageSize is the page size used by the event log iterators
+// when the filters do not set PerPage
+const defaultEventLogPageSize = 100
+
+// EventLogIter returns an iterator over a template's event log that fetches
+// one page at a time. Iteration starts at filters.Page (or the first page)
+// and stops at the first short page. An error is yielded once and ends the
+// iteration.
+func (s *ConsoleService) EventLogIter(ctx context.Context, templateID string, filters models.EventLogFilters) iter.Seq2[models.Event, error] {
+	return func(yield func(models.Event, error) bool) {
+		if filters.Page < 1 {
+			filters.Page = 1
+		}
+		if filters.PerPage < 1 {
+			filters.PerPage = defaultEventLogPageSize
+		}
+
+		for {
+			events, err := s.EventLog(ctx, templateID, filters)
+			if err != nil {
+				yield(models.Event{}, fmt.Errorf("page %d: %w", filters.Page, err))
+				return
+			}
+			for _, event := range events {
+				if !yield(event, nil) {
+					return
+				}
+			}
+			if len(events) < filters.PerPage {
+				return
+			}
+			filters.Page++
+		}
+	}
+}
+
+// AccountEventLogIter returns an iterator over the event logs of every
+// template returned by ListTemplates, merged in timestamp order. The order
+// follows filters.Sort and defaults to ascending. Events with equal
+// timestamps are yielded in template order.
+func (s *ConsoleService) AccountEventLogIter(ctx context.Context, filters models.EventLogFilters) iter.Seq2[models.Event, error] {
+	return func(yield func(models.Event, error) bool) {
+		templates, err := s.ListTemplates(ctx)
+		if err != nil {
+			yield(models.Event{}, err)
+			return
+		}
+
+		if filters.Sort == "" {
+			filters.Sort = models.SortAscending
+		}
+		descending := filters.Sort == models.SortDescending
+
+		type stream struct {
+			templateID string
+			next       func() (models.Event, error, bool)
+			head       models.Event
+		}
+
+		streams := make([]*stream, 0, len(templates))
+
+		advance := func(st *stream) (bool, error) {
+			event, err, ok := st.next()
+			if !ok {
+				return false, nil
+			}
+			if err != nil {
+				return false, fmt.Errorf("template %s: %w", st.templateID, err)
+			}
+			st.head = event
+			return true, nil
+		}
+
+		for _, template := range templates {
+			next, stop := iter.Pull2(s.EventLogIter(ctx, template.ID, filters))
+			defer stop()
+
+			st := &stream{templateID: template.ID, next: next}
+			ok, err := advance(st)
+			if err != nil {
+				yield(models.Event{}, err)
+				return
+			}
+			if ok {
+				streams = append(streams, st)
+			}
+		}
+
+		for len(streams) > 0 {
+			best := 0
+			for i, st := range streams[1:] {
+				head, bestHead := st.head.Timestamp, streams[best].head.Timestamp
+				if (!descending && head.Before(bestHead)) || (descending && head.After(bestHead)) {
+					best = i + 1
+				}
+			}
+
+			st := streams[best]
+			if !yield(st.head, nil) {
+				return
+			}
+
+			ok, err := advance(st)
+			if err != nil {
+				yield(models.Event{}, err)
+				return
+			}
+			if !ok {
+				streams = append(streams[:best], streams[best+1:]...)
+			}
+		}
+	}
+}
+
+// AccountEventLog collects the merged event logs of every template. See
+// AccountEventLogIter for ordering.
+func (s *ConsoleService) AccountEventLog(ctx context.Context, filters models.EventLogFilters) ([]models.Event, error) {
+	var events []models.Event
+	for event, err := range s.AccountEventLogIter(ctx, filters) {
+		if err != nil {
+			return nil, fmt.Errorf("error fetching account event log: %w", err)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Access-Grid/accessgrid-go/client"
+	"github.com/Access-Grid/accessgrid-go/models"
+)
+
+// eventLogServer serves paginated event logs for a fixed set of templates
+// and records the query of every log request
+type eventLogServer struct {
+	mu      sync.Mutex
+	logs    map[string][]models.Event
+	order   []string
+	queries []string
+}
+
+func (s *eventLogServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.URL.Path == "/v1/console/card-templates" {
+		templates := make([]models.Template, 0, len(s.order))
+		for _, id := range s.order {
+			templates = append(templates, models.Template{ID: id})
+		}
+		json.NewEncoder(w).Encode(templates)
+		return
+	}
+
+	templateID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/console/card-templates/"), "/logs")
+	events, ok := s.logs[templateID]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message": "template not found"}`))
+		return
+	}
+
+	query := r.URL.Query()
+	s.mu.Lock()
+	s.queries = append(s.queries, r.URL.RawQuery)
+	s.mu.Unlock()
+
+	if query.Get("sort") == string(models.SortDescending) {
+		reversed := make([]models.Event, len(events))
+		for i, event := range events {
+			reversed[len(events)-1-i] = event
+		}
+		events = reversed
+	}
+
+	page, _ := strconv.Atoi(query.Get("page"))
+	perPage, _ := strconv.Atoi(query.Get("per_page"))
+	if page > 0 && perPage > 0 {
+		start := min((page-1)*perPage, len(events))
+		end := min(start+perPage, len(events))
+		events = events[start:end]
+	}
+	json.NewEncoder(w).Encode(events)
+}
+
+func setupEventLogTestServer(t *testing.T, logs map[string][]models.Event, order ...string) (*eventLogServer, *ConsoleService) {
+	t.Helper()
+	backend := &eventLogServer{logs: logs, order: order}
+	server := httptest.NewServer(backend)
+	t.Cleanup(server.Close)
+
+	c, _ := client.NewClient("test-account", "test-secret", client.WithBaseURL(server.URL))
+	return backend, NewConsoleService(c)
+}
+
+func eventsAt(prefix string, base time.Time, minutes ...int) []models.Event {
+	events := make([]models.Event, 0, len(minutes))
+	for i, m := range minutes {
+		events = append(events, models.Event{
+			ID:        prefix + strconv.Itoa(i+1),
+			Timestamp: base.Add(time.Duration(m) * time.Minute),
+		})
+	}
+	return events
+}
+
+func eventIDs(events []models.Event) []string {
+	ids := make([]string, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+func TestEventLogQuery(t *testing.T) {
+	query := eventLogQuery(models.EventLogFilters{
+		Device:     "watch",
+		Devices:    []string{"phone"},
+		EventType:  models.EventTypeInstall,
+		EventTypes: []models.EventType{models.EventTypeUninstall, models.EventTypeTap},
+		CardID:     "0xc4rd1d",
+		UserID:     "usr_1",
+		Sort:       models.SortDescending,
+		Page:       2,
+		PerPage:    50,
+	})
+
+	want := map[string][]string{
+		"device":     {"watch", "phone"},
+		"event_type": {"install", "uninstall", "tap"},
+		"card_id":    {"0xc4rd1d"},
+		"user_id":    {"usr_1"},
+		"sort":       {"desc"},
+		"page":       {"2"},
+		"per_page":   {"50"},
+	}
+	for key, values := range want {
+		if got := query[key]; !reflect.DeepEqual(got, values) {
+			t.Errorf("eventLogQuery()[%q] = %v, want %v", key, got, values)
+		}
+	}
+}
+
+func TestConsoleService_EventLogIter(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	backend, service := setupEventLogTestServer(t, map[string][]models.Event{
+		"0xt1": eventsAt("evt_", base, 1, 2, 3, 4, 5),
+	}, "0xt1")
+
+	var events []models.Event
+	for event, err := range service.EventLogIter(context.Background(), "0xt1", models.EventLogFilters{PerPage: 2}) {
+		if err != nil {
+			t.Fatalf("EventLogIter() error = %v", err)
+		}
+		events = append(events, event)
+	}
+
+	if got, want := eventIDs(events), []string{"evt_1", "evt_2", "evt_3", "evt_4", "evt_5"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("EventLogIter() = %v, want %v", got, want)
+	}
+	if len(backend.queries) != 3 {
+		t.Errorf("EventLogIter() made %d requests, want 3", len(backend.queries))
+	}
+}
+
+func TestConsoleService_EventLogIter_StopsEarly(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	backend, service := setupEventLogTestServer(t, map[string][]models.Event{
+		"0xt1": eventsAt("evt_", base, 1, 2, 3, 4, 5),
+	}, "0xt1")
+
+	for range service.EventLogIter(context.Background(), "0xt1", models.EventLogFilters{PerPage: 2}) {
+		break
+	}
+	if len(backend.queries) != 1 {
+		t.Errorf("EventLogIter() made %d requests after break, want 1", len(backend.queries))
+	}
+}
+
+func TestConsoleService_AccountEventLog(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	logs := map[string][]models.Event{
+		"0xt1": eventsAt("a", base, 1, 4, 6),
+		"0xt2": eventsAt("b", base, 2, 3, 6, 9),
+		"0xt3": nil,
+	}
+
+	tests := []struct {
+		name string
+		sort models.SortOrder
+		want []string
+	}{
+		{"Ascending by default", "", []string{"a1", "b1", "b2", "a2", "a3", "b3", "b4"}},
+		{"Descending", models.SortDescending, []string{"b4", "a3", "b3", "a2", "b2", "b1", "a1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, service := setupEventLogTestServer(t, logs, "0xt1", "0xt2", "0xt3")
+
+			events, err := service.AccountEventLog(context.Background(), models.EventLogFilters{Sort: tt.sort, PerPage: 2})
+			if err != nil {
+				t.Fatalf("AccountEventLog() error = %v", err)
+			}
+			if got := eventIDs(events); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AccountEventLog() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConsoleService_AccountEventLog_Error(t *testing.T) {
+	_, service := setupEventLogTestServer(t, map[string][]models.Event{"0xt1": nil}, "0xt1", "0xmissing")
+
+	if _, err := service.AccountEventLog(context.Background(), models.EventLogFilters{}); err == nil {
+		t.Error("AccountEventLog() error = nil, want error for missing template log")
+	}
+}
@@ -0,0 +1,298 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Access-Grid/accessgrid-go/internal/atomicfile"
+	"github.com/Access-Grid/accessgrid-go/models"
+)
+
+// Default polling intervals for WatchEvents
+const (
+	DefaultWatchMinInterval = 5 * time.Second
+	DefaultWatchMaxInterval = 2 * time.Minute
+)
+
+// CheckpointStore persists event watch checkpoints so a watch can resume
+// where it left off after a restart
+type CheckpointStore interface {
+	// Load returns the checkpoint for a template, or nil if there is none
+	Load(ctx context.Context, templateID string) (*models.EventCheckpoint, error)
+	Save(ctx context.Context, templateID string, checkpoint models.EventCheckpoint) error
+}
+
+// MemoryCheckpointStore is a CheckpointStore held in memory
+type MemoryCheckpointStore struct {
+	mu          sync.Mutex
+	checkpoints map[string]models.EventCheckpoint
+}
+
+// NewMemoryCheckpointStore creates an empty MemoryCheckpointStore
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{checkpoints: make(map[string]models.EventCheckpoint)}
+}
+
+// Load implements CheckpointStore
+func (s *MemoryCheckpointStore) Load(ctx context.Context, templateID string) (*models.EventCheckpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	checkpoint, ok := s.checkpoints[templateID]
+	if !ok {
+		return nil, nil
+	}
+	return &checkpoint, nil
+}
+
+// Save implements CheckpointStore
+func (s *MemoryCheckpointStore) Save(ctx context.Context, templateID string, checkpoint models.EventCheckpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.checkpoints[templateID] = checkpoint
+	return nil
+}
+
+// FileCheckpointStore is a CheckpointStore persisted as a JSON file
+type FileCheckpointStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileCheckpointStore creates a FileCheckpointStore backed by the file at
+// path. The file is created on the first Save.
+func NewFileCheckpointStore(path string) *FileCheckpointStore {
+	return &FileCheckpointStore{path: path}
+}
+
+// Load implements CheckpointStore
+func (s *FileCheckpointStore) Load(ctx context.Context, templateID string) (*models.EventCheckpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	checkpoints, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	checkpoint, ok := checkpoints[templateID]
+	if !ok {
+		return nil, nil
+	}
+	return &checkpoint, nil
+}
+
+// Save implements CheckpointStore
+func (s *FileCheckpointStore) Save(ctx context.Context, templateID string, checkpoint models.EventCheckpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	checkpoints, err := s.read()
+	if err != nil {
+		return err
+	}
+	checkpoints[templateID] = checkpoint
+
+	data, err := json.Marshal(checkpoints)
+	if err != nil {
+		return fmt.Errorf("error encoding checkpoints: %w", err)
+	}
+	if err := atomicfile.WriteFile(s.path, data, 0o644); err != nil {
+		return fmt.Errorf("error writing checkpoints: %w", err)
+	}
+	return nil
+}
+
+func (s *FileCheckpointStore) read() (map[string]models.EventCheckpoint, error) {
+	checkpoints := make(map[string]models.EventCheckpoint)
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return checkpoints, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading checkpoints: %w", err)
+	}
+	if err := json.Unmarshal(data, &checkpoints); err != nil {
+		return nil, fmt.Errorf("error parsing checkpoints: %w", err)
+	}
+	return checkpoints, nil
+}
+
+// WatchOptions configures WatchEvents
+type WatchOptions struct {
+	// Checkpoints persists progress. Defaults to an in-memory store, in
+	// which case a new watch starts from filters.StartDate.
+	Checkpoints CheckpointStore
+	// MinInterval is the polling interval while events are arriving
+	MinInterval time.Duration
+	// MaxInterval caps the polling interval as it backs off while idle
+	MaxInterval time.Duration
+	// OnError is called with the joined errors of every template that
+	// failed in a polling round, after which the watch backs off and
+	// retries. Templates that succeed are still polled each round. If nil,
+	// the first failed round stops the watch.
+	OnError func(error)
+}
+
+// EventWatch is a running WatchEvents call
+type EventWatch struct {
+	events chan models.Event
+	err    error
+}
+
+// Events returns the channel events are delivered on. It is closed when the
+// watch stops.
+func (w *EventWatch) Events() <-chan models.Event {
+	return w.events
+}
+
+// Err returns the error that stopped the watch. It is only valid once the
+// Events channel has been closed.
+func (w *EventWatch) Err() error {
+	return w.err
+}
+
+// WatchEvents polls the event logs of the given templates and delivers each
+// event once, in timestamp order per template, until ctx is cancelled.
+// The checkpoint for a template is saved after each event is received from
+// the channel, so a watch restarted with the same CheckpointStore resumes
+// without gaps. Polling slows down while no events arrive and speeds up again
+// once they do.
+func (s *ConsoleService) WatchEvents(ctx context.Context, templateIDs []string, filters models.EventLogFilters, opts WatchOptions) (*EventWatch, error) {
+	if len(templateIDs) == 0 {
+		return nil, errors.New("at least one template ID is required")
+	}
+	if opts.Checkpoints == nil {
+		opts.Checkpoints = NewMemoryCheckpointStore()
+	}
+	if opts.MinInterval <= 0 {
+		opts.MinInterval = DefaultWatchMinInterval
+	}
+	if opts.MaxInterval < opts.MinInterval {
+		opts.MaxInterval = DefaultWatchMaxInterval
+		if opts.MaxInterval < opts.MinInterval {
+			opts.MaxInterval = opts.MinInterval
+		}
+	}
+
+	watch := &EventWatch{events: make(chan models.Event)}
+	go func() {
+		defer close(watch.events)
+		watch.err = s.watch(ctx, watch.events, templateIDs, filters, opts)
+	}()
+	return watch, nil
+}
+
+func (s *ConsoleService) watch(ctx context.Context, out chan<- models.Event, templateIDs []string, filters models.EventLogFilters, opts WatchOptions) error {
+	interval := opts.MinInterval
+	for {
+		delivered := 0
+		var errs []error
+		for _, templateID := range templateIDs {
+			n, err := s.pollTemplate(ctx, out, templateID, filters, opts.Checkpoints)
+			delivered += n
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				// Keep polling the remaining templates so one failing
+				// template doesn't starve the others
+				errs = append(errs, fmt.Errorf("error watching template %s: %w", templateID, err))
+			}
+		}
+		roundErr := errors.Join(errs...)
+
+		switch {
+		case roundErr != nil && opts.OnError == nil:
+			return roundErr
+		case roundErr != nil:
+			opts.OnError(roundErr)
+			interval = min(interval*2, opts.MaxInterval)
+		case delivered > 0:
+			interval = opts.MinInterval
+		default:
+			interval = min(interval*2, opts.MaxInterval)
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// pollTemplate delivers the events for one template that are newer than its
+// checkpoint and returns how many were delivered
+func (s *ConsoleService) pollTemplate(ctx context.Context, out chan<- models.Event, templateID string, filters models.EventLogFilters, checkpoints CheckpointStore) (int, error) {
+	checkpoint, err := checkpoints.Load(ctx, templateID)
+	if err != nil {
+		return 0, err
+	}
+	if checkpoint == nil {
+		checkpoint = &models.EventCheckpoint{}
+		if filters.StartDate != nil {
+			checkpoint.Timestamp = *filters.StartDate
+		}
+	}
+
+	if !checkpoint.Timestamp.IsZero() {
+		since := checkpoint.Timestamp
+		filters.StartDate = &since
+	}
+	events, err := s.EventLog(ctx, templateID, filters)
+	if err != nil {
+		return 0, err
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].Timestamp.Equal(events[j].Timestamp) {
+			return events[i].Timestamp.Before(events[j].Timestamp)
+		}
+		return events[i].ID < events[j].ID
+	})
+
+	seen := make(map[string]bool, len(checkpoint.SeenIDs))
+	for _, id := range checkpoint.SeenIDs {
+		seen[id] = true
+	}
+
+	delivered := 0
+	for _, event := range events {
+		if event.Timestamp.Before(checkpoint.Timestamp) {
+			continue
+		}
+		if event.Timestamp.Equal(checkpoint.Timestamp) && seen[event.ID] {
+			continue
+		}
+
+		select {
+		case out <- event:
+		case <-ctx.Done():
+			return delivered, ctx.Err()
+		}
+		delivered++
+
+		if event.Timestamp.After(checkpoint.Timestamp) {
+			checkpoint.Timestamp = event.Timestamp
+			checkpoint.SeenIDs = nil
+			seen = make(map[string]bool)
+		}
+		checkpoint.SeenIDs = append(checkpoint.SeenIDs, event.ID)
+		seen[event.ID] = true
+
+		if err := checkpoints.Save(ctx, templateID, *checkpoint); err != nil {
+			return delivered, err
+		}
+	}
+
+	return delivered, nil
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Access-Grid/accessgrid-go/client"
+	"github.com/Access-Grid/accessgrid-go/models"
+)
+
+// eventFeed serves a growing event log that honors start_date inclusively,
+// so consecutive polls return overlapping windows
+type eventFeed struct {
+	mu     sync.Mutex
+	events []models.Event
+}
+
+func (f *eventFeed) add(events ...models.Event) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, events...)
+}
+
+func setupEventWatchTestServer(feed *eventFeed) (*httptest.Server, *ConsoleService) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/v1/console/card-templates/0xd3adb00b5/logs" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		var since time.Time
+		if v := r.URL.Query().Get("start_date"); v != "" {
+			since, _ = time.Parse(time.RFC3339, v)
+		}
+
+		feed.mu.Lock()
+		defer feed.mu.Unlock()
+		events := []models.Event{}
+		for _, event := range feed.events {
+			if !event.Timestamp.Before(since) {
+				events = append(events, event)
+			}
+		}
+		json.NewEncoder(w).Encode(events)
+	}))
+
+	c, _ := client.NewClient("test-account", "test-secret", client.WithBaseURL(server.URL))
+	return server, NewConsoleService(c)
+}
+
+func receiveEvents(t *testing.T, watch *EventWatch, n int) []string {
+	t.Helper()
+	var ids []string
+	for len(ids) < n {
+		select {
+		case event, ok := <-watch.Events():
+			if !ok {
+				t.Fatalf("watch stopped early: %v", watch.Err())
+			}
+			ids = append(ids, event.ID)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out after receiving %v", ids)
+		}
+	}
+	return ids
+}
+
+func TestConsoleService_WatchEventsResumes(t *testing.T) {
+	t1, _ := time.Parse(time.RFC3339, "2023-01-01T12:00:00Z")
+	t2 := t1.Add(time.Minute)
+
+	feed := &eventFeed{}
+	feed.add(
+		models.Event{ID: "evt_2", Timestamp: t1},
+		models.Event{ID: "evt_1", Timestamp: t1},
+	)
+	server, service := setupEventWatchTestServer(feed)
+	defer server.Close()
+
+	checkpoints := NewFileCheckpointStore(filepath.Join(t.TempDir(), "checkpoints.json"))
+	opts := WatchOptions{
+		Checkpoints: checkpoints,
+		MinInterval: 5 * time.Millisecond,
+		MaxInterval: 20 * time.Millisecond,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	watch, err := service.WatchEvents(ctx, []string{"0xd3adb00b5"}, models.EventLogFilters{}, opts)
+	if err != nil {
+		t.Fatalf("WatchEvents() error = %v", err)
+	}
+
+	ids := receiveEvents(t, watch, 2)
+	if ids[0] != "evt_1" || ids[1] != "evt_2" {
+		t.Errorf("WatchEvents() got %v, want [evt_1 evt_2]", ids)
+	}
+
+	// Polls overlap the last timestamp; nothing is delivered twice
+	time.Sleep(50 * time.Millisecond)
+	feed.add(models.Event{ID: "evt_3", Timestamp: t2})
+	if ids := receiveEvents(t, watch, 1); ids[0] != "evt_3" {
+		t.Errorf("WatchEvents() got %v, want [evt_3]", ids)
+	}
+
+	cancel()
+	for range watch.Events() {
+		t.Error("WatchEvents() delivered an event after cancel")
+	}
+
+	// A new watch with the same checkpoints only sees later events
+	feed.add(models.Event{ID: "evt_4", Timestamp: t2}, models.Event{ID: "evt_5", Timestamp: t2.Add(time.Minute)})
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	watch, err = service.WatchEvents(ctx, []string{"0xd3adb00b5"}, models.EventLogFilters{}, opts)
+	if err != nil {
+		t.Fatalf("WatchEvents() error = %v", err)
+	}
+	ids = receiveEvents(t, watch, 2)
+	if ids[0] != "evt_4" || ids[1] != "evt_5" {
+		t.Errorf("WatchEvents() after restart got %v, want [evt_4 evt_5]", ids)
+	}
+}
+
+func TestConsoleService_WatchEventsStopsOnError(t *testing.T) {
+	server, service := setupEventWatchTestServer(&eventFeed{})
+	defer server.Close()
+
+	watch, err := service.WatchEvents(context.Background(), []string{"0xm1ss1ng"}, models.EventLogFilters{}, WatchOptions{})
+	if err != nil {
+		t.Fatalf("WatchEvents() error = %v", err)
+	}
+
+	for range watch.Events() {
+	}
+	if !client.IsNotFound(watch.Err()) {
+		t.Errorf("Err() = %v, want not found", watch.Err())
+	}
+}
+
+func TestConsoleService_WatchEventsSkipsFailingTemplate(t *testing.T) {
+	t1, _ := time.Parse(time.RFC3339, "2023-01-01T12:00:00Z")
+	feed := &eventFeed{}
+	feed.add(models.Event{ID: "evt_1", Timestamp: t1})
+
+	server, service := setupEventWatchTestServer(feed)
+	defer server.Close()
+
+	var mu sync.Mutex
+	var failures int
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The first template always fails; the second must still be polled
+	watch, err := service.WatchEvents(ctx, []string{"0xm1ss1ng", "0xd3adb00b5"}, models.EventLogFilters{}, WatchOptions{
+		MinInterval: time.Millisecond,
+		MaxInterval: 5 * time.Millisecond,
+		OnError: func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			failures++
+		},
+	})
+	if err != nil {
+		t.Fatalf("WatchEvents() error = %v", err)
+	}
+
+	receiveEvents(t, watch, 1)
+	feed.add(models.Event{ID: "evt_2", Timestamp: t1.Add(time.Minute)})
+	if ids := receiveEvents(t, watch, 1); ids[0] != "evt_2" {
+		t.Errorf("received %v, want evt_2", ids)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if failures == 0 {
+		t.Error("OnError was not called for the failing template")
+	}
+}
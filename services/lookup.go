@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Access-Grid/accessgrid-go/models"
+)
+
+// Lookup fields reported in models.CardLookup and models.LookupError
+const (
+	lookupFieldEmployeeID = "employee_id"
+	lookupFieldEmail      = "email"
+	lookupFieldCardNumber = "card_number"
+)
+
+// lookupKeyAllCards caches the unfiltered card listing shared by lookups the
+// API can't filter on
+const lookupKeyAllCards = "*"
+
+// lookupCache holds recent lookup results for a short, configurable time
+type lookupCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]lookupCacheEntry
+}
+
+type lookupCacheEntry struct {
+	cards   []models.Card
+	expires time.Time
+}
+
+func (c *lookupCache) get(key string) ([]models.Card, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return cloneCards(entry.cards), true
+}
+
+func (c *lookupCache) put(key string, cards []models.Card) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ttl <= 0 {
+		return
+	}
+	if c.entries == nil {
+		c.entries = make(map[string]lookupCacheEntry)
+	}
+	c.entries[key] = lookupCacheEntry{cards: cloneCards(cards), expires: time.Now().Add(c.ttl)}
+}
+
+// cloneCards deep-copies cards so the cache and its callers never share
+// devices, metadata or details
+func cloneCards(cards []models.Card) []models.Card {
+	if cards == nil {
+		return nil
+	}
+	cloned := make([]models.Card, len(cards))
+	for i, card := range cards {
+		card.Devices = slices.Clone(card.Devices)
+		if card.Metadata != nil {
+			card.Metadata = cloneJSONValue(card.Metadata).(map[string]interface{})
+		}
+		card.Details = cloneJSONValue(card.Details)
+		cloned[i] = card
+	}
+	return cloned
+}
+
+// cloneJSONValue deep-copies the maps and slices of a decoded JSON value
+func cloneJSONValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		cloned := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			cloned[k] = cloneJSONValue(item)
+		}
+		return cloned
+	case []interface{}:
+		cloned := make([]interface{}, len(v))
+		for i, item := range v {
+			cloned[i] = cloneJSONValue(item)
+		}
+		return cloned
+	}
+	return v
+}
+
+func (c *lookupCache) configure(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ttl = ttl
+	c.entries = nil
+}
+
+func (c *lookupCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = nil
+}
+
+// SetLookupCacheTTL enables caching of FindBy* results for the given
+// duration. A zero or negative TTL disables the cache. Changing the TTL
+// clears any cached results.
+func (s *AccessCardsService) SetLookupCacheTTL(ttl time.Duration) {
+	s.lookupCache.configure(ttl)
+}
+
+// ClearLookupCache discards any cached FindBy* results
+func (s *AccessCardsService) ClearLookupCache() {
+	s.lookupCache.clear()
+}
+
+// FindByEmployee retrieves every card issued to an employee across all
+// templates
+func (s *AccessCardsService) FindByEmployee(ctx context.Context, employeeID string) (*models.CardLookup, error) {
+	return s.find(ctx, lookupFieldEmployeeID, employeeID, &models.ListKeysParams{EmployeeID: employeeID}, func(card models.Card) bool {
+		return card.EmployeeID == employeeID
+	})
+}
+
+// FindByEmail retrieves every card whose email matches, ignoring case,
+// across all templates. The API can't filter by email, so each lookup lists
+// every card on the account. With the lookup cache enabled that listing is
+// made once per TTL and shared by all email lookups.
+func (s *AccessCardsService) FindByEmail(ctx context.Context, email string) (*models.CardLookup, error) {
+	return s.find(ctx, lookupFieldEmail, email, nil, func(card models.Card) bool {
+		return strings.EqualFold(card.Email, email)
+	})
+}
+
+// FindByCardNumber retrieves every card with the given card number across
+// all templates
+func (s *AccessCardsService) FindByCardNumber(ctx context.Context, cardNumber string) (*models.CardLookup, error) {
+	return s.find(ctx, lookupFieldCardNumber, cardNumber, &models.ListKeysParams{CardNumber: cardNumber}, func(card models.Card) bool {
+		return card.CardNumber == cardNumber
+	})
+}
+
+// find lists cards with params and keeps those accepted by match. Results
+// are filtered locally as well so lookups by fields the API can't filter on
+// still work. The listing, rather than the matches, is cached, so a nil
+// params listing is shared by every such lookup.
+func (s *AccessCardsService) find(ctx context.Context, field, value string, params *models.ListKeysParams, match func(models.Card) bool) (*models.CardLookup, error) {
+	if value == "" {
+		return nil, fmt.Errorf("%s is required", field)
+	}
+
+	key := field + ":" + value
+	if params == nil {
+		key = lookupKeyAllCards
+	}
+	cards, ok := s.lookupCache.get(key)
+	if !ok {
+		var err error
+		cards, err = s.List(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("error looking up cards by %s: %w", field, err)
+		}
+		s.lookupCache.put(key, cards)
+	}
+
+	var matches []models.Card
+	for _, card := range cards {
+		if match(card) {
+			matches = append(matches, card)
+		}
+	}
+	return models.NewCardLookup(field, value, matches), nil
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Access-Grid/accessgrid-go/client"
+	"github.com/Access-Grid/accessgrid-go/models"
+)
+
+func setupLookupTestServer() (*httptest.Server, *AccessCardsService, *int32) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		switch r.URL.Path {
+		case "/v1/key-cards":
+			w.Write([]byte(`{
+				"keys": [
+					{
+						"id": "0xc4rd1d",
+						"card_template_id": "0xd3adb00b5",
+						"employee_id": "123456789",
+						"card_number": "12345",
+						"email": "Employee@Example.com",
+						"state": "active",
+						"devices": [{"id": "dev_1", "status": "active"}],
+						"metadata": {"badge": {"floor": 3}}
+					},
+					{
+						"id": "0xc4rd2d",
+						"card_template_id": "0xp4rk1ng",
+						"employee_id": "123456789",
+						"card_number": "67890",
+						"email": "employee@example.com",
+						"state": "active"
+					},
+					{
+						"id": "0xc4rd3d",
+						"card_template_id": "0xd3adb00b5",
+						"employee_id": "555555555",
+						"card_number": "11111",
+						"email": "other@example.com",
+						"state": "active"
+					}
+				]
+			}`))
+		}
+	}))
+
+	c, _ := client.NewClient("test-account", "test-secret", client.WithBaseURL(server.URL))
+	service := NewAccessCardsService(c)
+
+	return server, service, &requests
+}
+
+func TestAccessCardsService_FindByEmployee(t *testing.T) {
+	server, service, _ := setupLookupTestServer()
+	defer server.Close()
+
+	lookup, err := service.FindByEmployee(context.Background(), "123456789")
+	if err != nil {
+		t.Fatalf("FindByEmployee() error = %v", err)
+	}
+
+	if len(lookup.Cards) != 2 {
+		t.Fatalf("FindByEmployee() got %v cards, want %v", len(lookup.Cards), 2)
+	}
+	if len(lookup.ByTemplate) != 2 {
+		t.Errorf("FindByEmployee() got %v templates, want %v", len(lookup.ByTemplate), 2)
+	}
+	if !lookup.Ambiguous() {
+		t.Error("FindByEmployee() expected ambiguous lookup")
+	}
+
+	var lookupErr *models.LookupError
+	if _, err := lookup.Single(); !errors.As(err, &lookupErr) || len(lookupErr.CardIDs) != 2 {
+		t.Errorf("Single() error = %v, want LookupError with 2 card IDs", err)
+	}
+}
+
+func TestAccessCardsService_FindByEmail(t *testing.T) {
+	server, service, _ := setupLookupTestServer()
+	defer server.Close()
+
+	lookup, err := service.FindByEmail(context.Background(), "EMPLOYEE@example.com")
+	if err != nil {
+		t.Fatalf("FindByEmail() error = %v", err)
+	}
+
+	if len(lookup.Cards) != 2 {
+		t.Errorf("FindByEmail() got %v cards, want %v", len(lookup.Cards), 2)
+	}
+}
+
+func TestAccessCardsService_FindByCardNumber(t *testing.T) {
+	server, service, _ := setupLookupTestServer()
+	defer server.Close()
+
+	lookup, err := service.FindByCardNumber(context.Background(), "67890")
+	if err != nil {
+		t.Fatalf("FindByCardNumber() error = %v", err)
+	}
+
+	card, err := lookup.Single()
+	if err != nil {
+		t.Fatalf("Single() error = %v", err)
+	}
+	if card.ID != "0xc4rd2d" {
+		t.Errorf("Single() card.ID = %v, want %v", card.ID, "0xc4rd2d")
+	}
+
+	lookup, err = service.FindByCardNumber(context.Background(), "00000")
+	if err != nil {
+		t.Fatalf("FindByCardNumber() error = %v", err)
+	}
+	if _, err := lookup.Single(); err == nil {
+		t.Error("Single() expected error for no matches")
+	}
+}
+
+func TestAccessCardsService_LookupCache(t *testing.T) {
+	server, service, requests := setupLookupTestServer()
+	defer server.Close()
+
+	service.SetLookupCacheTTL(time.Minute)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := service.FindByEmployee(ctx, "123456789"); err != nil {
+			t.Fatalf("FindByEmployee() error = %v", err)
+		}
+	}
+	if got := atomic.LoadInt32(requests); got != 1 {
+		t.Errorf("FindByEmployee() made %v requests, want %v", got, 1)
+	}
+
+	service.ClearLookupCache()
+	if _, err := service.FindByEmployee(ctx, "123456789"); err != nil {
+		t.Fatalf("FindByEmployee() error = %v", err)
+	}
+	if got := atomic.LoadInt32(requests); got != 2 {
+		t.Errorf("FindByEmployee() made %v requests after clear, want %v", got, 2)
+	}
+}
+
+func TestAccessCardsService_LookupCacheIsolation(t *testing.T) {
+	server, service, requests := setupLookupTestServer()
+	defer server.Close()
+
+	service.SetLookupCacheTTL(time.Minute)
+	ctx := context.Background()
+
+	lookup, err := service.FindByCardNumber(ctx, "12345")
+	if err != nil {
+		t.Fatalf("FindByCardNumber() error = %v", err)
+	}
+	lookup.Cards[0].ID = "0xm0d1f13d"
+	lookup.Cards[0].Devices[0].ID = "dev_m0d1f13d"
+	lookup.Cards[0].Metadata["badge"].(map[string]interface{})["floor"] = 9
+
+	lookup, err = service.FindByCardNumber(ctx, "12345")
+	if err != nil {
+		t.Fatalf("FindByCardNumber() error = %v", err)
+	}
+	if lookup.Cards[0].ID != "0xc4rd1d" {
+		t.Errorf("FindByCardNumber() after caller modification got %v, want %v", lookup.Cards[0].ID, "0xc4rd1d")
+	}
+	if card := lookup.Cards[0]; card.Devices[0].ID != "dev_1" || card.Metadata["badge"].(map[string]interface{})["floor"] != 3.0 {
+		t.Errorf("FindByCardNumber() after caller modification got devices %v, metadata %v", card.Devices, card.Metadata)
+	}
+
+	// Email lookups share a single cached listing
+	atomic.StoreInt32(requests, 0)
+	for _, email := range []string{"employee@example.com", "other@example.com"} {
+		if _, err := service.FindByEmail(ctx, email); err != nil {
+			t.Fatalf("FindByEmail() error = %v", err)
+		}
+	}
+	if got := atomic.LoadInt32(requests); got != 1 {
+		t.Errorf("FindByEmail() made %v requests, want %v", got, 1)
+	}
+}
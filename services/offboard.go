@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Access-Grid/accessgrid-go/client"
+	"github.com/Access-Grid/accessgrid-go/models"
+)
+
+// Offboard actions recorded in models.OffboardReport
+const (
+	offboardActionSuspend = "suspend"
+	offboardActionUnlink  = "unlink"
+	offboardActionDelete  = "delete"
+)
+
+// Offboard revokes every card held by an employee across all templates. All
+// cards are suspended concurrently, then optionally unlinked from their
+// devices and deleted once the grace period has elapsed.
+//
+// Offboard is idempotent: cards already suspended or deleted are skipped,
+// so it can be re-run after a partial failure or once the grace period has
+// passed. The returned report records every action; the returned error
+// joins any failures.
+//
+// The grace period runs from when each card was suspended. For cards
+// suspended by this call that is now; for cards already suspended it is the
+// suspend event in the event log, or the suspend recorded in
+// params.Previous. A card whose suspension time can't be found is left
+// pending rather than deleted early.
+func (s *AccessCardsService) Offboard(ctx context.Context, employeeID string, params models.OffboardParams) (*models.OffboardReport, error) {
+	if employeeID == "" {
+		return nil, errors.New("employeeID is required")
+	}
+
+	report := &models.OffboardReport{EmployeeID: employeeID, StartedAt: time.Now()}
+
+	// Bypass the lookup cache so no recently issued card is missed
+	listed, err := s.List(ctx, &models.ListKeysParams{EmployeeID: employeeID})
+	if err != nil {
+		report.CompletedAt = time.Now()
+		return report, fmt.Errorf("error offboarding employee: %w", err)
+	}
+
+	var cards []models.Card
+	for _, card := range listed {
+		if card.EmployeeID == employeeID && card.State != models.CardStateDeleted {
+			cards = append(cards, card)
+		}
+	}
+
+	actions := make([][]models.OffboardAction, len(cards))
+	var wg sync.WaitGroup
+	for i, card := range cards {
+		wg.Add(1)
+		go func(i int, card models.Card) {
+			defer wg.Done()
+			actions[i] = s.offboardCard(ctx, card, params)
+		}(i, card)
+	}
+	wg.Wait()
+
+	var errs []error
+	report.Complete = true
+	for _, cardActions := range actions {
+		for _, action := range cardActions {
+			switch action.Status {
+			case models.OffboardStatusFailed:
+				errs = append(errs, fmt.Errorf("%s card %s: %s", action.Action, action.CardID, action.Detail))
+				report.Complete = false
+			case models.OffboardStatusPending:
+				report.Complete = false
+			}
+		}
+		report.Actions = append(report.Actions, cardActions...)
+	}
+	report.CompletedAt = time.Now()
+
+	if len(cards) > 0 {
+		s.ClearLookupCache()
+	}
+
+	return report, errors.Join(errs...)
+}
+
+// offboardCard suspends, unlinks and deletes a single card as requested,
+// stopping at the first action that fails or finds the card gone
+func (s *AccessCardsService) offboardCard(ctx context.Context, card models.Card, params models.OffboardParams) []models.OffboardAction {
+	var actions []models.OffboardAction
+	record := func(action, status, detail string) {
+		actions = append(actions, models.OffboardAction{
+			CardID:     card.ID,
+			TemplateID: card.CardTemplateID,
+			Action:     action,
+			Status:     status,
+			Detail:     detail,
+			At:         time.Now(),
+		})
+	}
+
+	var suspendedAt time.Time
+	if card.State == models.CardStateSuspended {
+		record(offboardActionSuspend, models.OffboardStatusSkipped, "already suspended")
+	} else {
+		err := s.Suspend(ctx, card.ID)
+		if client.IsNotFound(err) {
+			record(offboardActionSuspend, models.OffboardStatusSkipped, "card no longer exists")
+			return actions
+		}
+		if err != nil {
+			record(offboardActionSuspend, models.OffboardStatusFailed, err.Error())
+			return actions
+		}
+		record(offboardActionSuspend, models.OffboardStatusDone, "")
+		suspendedAt = time.Now()
+	}
+
+	if params.UnlinkDevices {
+		err := s.Unlink(ctx, card.ID)
+		if client.IsNotFound(err) {
+			record(offboardActionUnlink, models.OffboardStatusSkipped, "card no longer exists")
+			return actions
+		}
+		if err != nil {
+			record(offboardActionUnlink, models.OffboardStatusFailed, err.Error())
+			return actions
+		}
+		record(offboardActionUnlink, models.OffboardStatusDone, "")
+	}
+
+	if params.Delete {
+		if suspendedAt.IsZero() {
+			var err error
+			suspendedAt, err = s.suspensionTime(ctx, card, params.Previous)
+			if err != nil {
+				record(offboardActionDelete, models.OffboardStatusFailed, err.Error())
+				return actions
+			}
+			if suspendedAt.IsZero() {
+				record(offboardActionDelete, models.OffboardStatusPending, "suspension time unknown; pass the previous report to start the grace period")
+				return actions
+			}
+		}
+		eligibleAt := suspendedAt.Add(params.GracePeriod)
+		if time.Now().Before(eligibleAt) {
+			record(offboardActionDelete, models.OffboardStatusPending, fmt.Sprintf("grace period ends %s", eligibleAt.Format(time.RFC3339)))
+			return actions
+		}
+		err := s.Delete(ctx, card.ID)
+		if client.IsNotFound(err) {
+			record(offboardActionDelete, models.OffboardStatusSkipped, "card no longer exists")
+			return actions
+		}
+		if err != nil {
+			record(offboardActionDelete, models.OffboardStatusFailed, err.Error())
+			return actions
+		}
+		record(offboardActionDelete, models.OffboardStatusDone, "")
+	}
+
+	return actions
+}
+
+// suspensionTime returns when an already suspended card was suspended: the
+// latest suspend event in the card's event log or, failing that, a suspend
+// recorded in previous. It returns the zero time if neither has one. The
+// card's UpdatedAt is not used, since any later edit would move it.
+func (s *AccessCardsService) suspensionTime(ctx context.Context, card models.Card, previous *models.OffboardReport) (time.Time, error) {
+	console := NewConsoleService(s.client)
+	events, err := console.EventLog(ctx, card.CardTemplateID, models.EventLogFilters{
+		CardID:    card.ID,
+		EventType: models.EventTypeSuspend,
+		Sort:      models.SortDescending,
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error finding suspension time: %w", err)
+	}
+
+	var suspendedAt time.Time
+	for _, event := range events {
+		// Filter locally as well in case the API ignores a filter
+		if event.CardID == card.ID && event.Type == models.EventTypeSuspend && event.Timestamp.After(suspendedAt) {
+			suspendedAt = event.Timestamp
+		}
+	}
+	if !suspendedAt.IsZero() || previous == nil {
+		return suspendedAt, nil
+	}
+
+	for _, action := range previous.Actions {
+		if action.CardID == card.ID && action.Action == offboardActionSuspend && action.Status == models.OffboardStatusDone {
+			suspendedAt = action.At
+		}
+	}
+	return suspendedAt, nil
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Access-Grid/accessgrid-go/client"
+	"github.com/Access-Grid/accessgrid-go/models"
+)
+
+func setupOffboardTestServer() (*httptest.Server, *AccessCardsService, *sync.Map) {
+	calls := &sync.Map{}
+	recent := time.Now().UTC().Format(time.RFC3339)
+	old := time.Now().UTC().Add(-72 * time.Hour).Format(time.RFC3339)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		calls.Store(r.URL.Path, true)
+
+		switch r.URL.Path {
+		case "/v1/key-cards":
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, `{
+				"keys": [
+					{"id": "0xact1v3", "card_template_id": "0xd3adb00b5", "employee_id": "123456789", "state": "active"},
+					{"id": "0xsusp", "card_template_id": "0xp4rk1ng", "employee_id": "123456789", "state": "suspended", "updated_at": %[1]q},
+					{"id": "0xr3c3nt", "card_template_id": "0xp4rk1ng", "employee_id": "123456789", "state": "suspended", "updated_at": "2023-01-01T00:00:00Z"},
+					{"id": "0xn0l0g", "card_template_id": "0xp4rk1ng", "employee_id": "123456789", "state": "suspended", "updated_at": "2023-01-01T00:00:00Z"},
+					{"id": "0xf41l", "card_template_id": "0xd3adb00b5", "employee_id": "123456789", "state": "active"},
+					{"id": "0x0th3r", "card_template_id": "0xd3adb00b5", "employee_id": "555555555", "state": "active"}
+				]
+			}`, recent)
+		case "/v1/console/card-templates/0xp4rk1ng/logs":
+			// 0xsusp was suspended long ago but edited recently; 0xr3c3nt
+			// was suspended recently; 0xn0l0g has no suspend event
+			w.WriteHeader(http.StatusOK)
+			switch r.URL.Query().Get("card_id") {
+			case "0xsusp":
+				fmt.Fprintf(w, `[{"id": "e1", "type": "suspend", "card_id": "0xsusp", "timestamp": %q}]`, old)
+			case "0xr3c3nt":
+				fmt.Fprintf(w, `[{"id": "e2", "type": "suspend", "card_id": "0xr3c3nt", "timestamp": %q}]`, recent)
+			default:
+				w.Write([]byte(`[]`))
+			}
+		case "/v1/key-cards/0xf41l/suspend":
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"message": "suspend failed"}`))
+		case "/v1/key-cards/0xact1v3/suspend", "/v1/key-cards/0xact1v3/unlink", "/v1/key-cards/0xsusp/unlink",
+			"/v1/key-cards/0xr3c3nt/unlink", "/v1/key-cards/0xsusp/delete", "/v1/key-cards/0xn0l0g/unlink",
+			"/v1/key-cards/0xn0l0g/delete":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "not found"}`))
+		}
+	}))
+
+	c, _ := client.NewClient("test-account", "test-secret", client.WithBaseURL(server.URL))
+	service := NewAccessCardsService(c)
+
+	return server, service, calls
+}
+
+func TestAccessCardsService_Offboard(t *testing.T) {
+	server, service, calls := setupOffboardTestServer()
+	defer server.Close()
+
+	params := models.OffboardParams{
+		UnlinkDevices: true,
+		Delete:        true,
+		GracePeriod:   24 * time.Hour,
+	}
+
+	report, err := service.Offboard(context.Background(), "123456789", params)
+	if err == nil {
+		t.Error("Offboard() expected error for failed suspend")
+	}
+	if report.Complete {
+		t.Error("Offboard() expected incomplete report")
+	}
+
+	statuses := make(map[string]string)
+	for _, action := range report.Actions {
+		statuses[action.CardID+"/"+action.Action] = action.Status
+	}
+
+	want := map[string]string{
+		"0xact1v3/suspend": models.OffboardStatusDone,
+		"0xact1v3/unlink":  models.OffboardStatusDone,
+		"0xact1v3/delete":  models.OffboardStatusPending,
+		"0xsusp/suspend":   models.OffboardStatusSkipped,
+		"0xsusp/unlink":    models.OffboardStatusDone,
+		"0xsusp/delete":    models.OffboardStatusDone,
+		"0xr3c3nt/delete":  models.OffboardStatusPending,
+		"0xn0l0g/delete":   models.OffboardStatusPending,
+		"0xf41l/suspend":   models.OffboardStatusFailed,
+	}
+	for key, status := range want {
+		if statuses[key] != status {
+			t.Errorf("Offboard() %s status = %v, want %v", key, statuses[key], status)
+		}
+	}
+	if _, ok := statuses["0xf41l/unlink"]; ok {
+		t.Error("Offboard() unexpectedly unlinked a card that failed to suspend")
+	}
+	if _, ok := calls.Load("/v1/key-cards/0x0th3r/suspend"); ok {
+		t.Error("Offboard() suspended another employee's card")
+	}
+}
+
+func TestAccessCardsService_OffboardSuspendOnly(t *testing.T) {
+	server, service, calls := setupOffboardTestServer()
+	defer server.Close()
+
+	report, _ := service.Offboard(context.Background(), "123456789", models.OffboardParams{})
+
+	if len(report.Actions) != 5 {
+		t.Errorf("Offboard() got %v actions, want %v", len(report.Actions), 5)
+	}
+	if _, ok := calls.Load("/v1/key-cards/0xsusp/delete"); ok {
+		t.Error("Offboard() deleted a card without Delete set")
+	}
+}
+
+func TestAccessCardsService_OffboardPreviousReport(t *testing.T) {
+	server, service, calls := setupOffboardTestServer()
+	defer server.Close()
+
+	// A previous run suspended 0xn0l0g two days ago, which the event log
+	// doesn't show
+	previous := &models.OffboardReport{Actions: []models.OffboardAction{
+		{CardID: "0xn0l0g", Action: "suspend", Status: models.OffboardStatusDone, At: time.Now().Add(-48 * time.Hour)},
+	}}
+	service.Offboard(context.Background(), "123456789", models.OffboardParams{
+		Delete:      true,
+		GracePeriod: 24 * time.Hour,
+		Previous:    previous,
+	})
+
+	if _, ok := calls.Load("/v1/key-cards/0xn0l0g/delete"); !ok {
+		t.Error("Offboard() did not delete a card suspended past the grace period in the previous report")
+	}
+	if _, ok := calls.Load("/v1/key-cards/0xr3c3nt/delete"); ok {
+		t.Error("Offboard() deleted a card still inside its grace period")
+	}
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Access-Grid/accessgrid-go/models"
+)
+
+// Reissue step names recorded in models.ReissueReport
+const (
+	reissueStepRead      = "read"
+	reissueStepProvision = "provision"
+	reissueStepSuspend   = "suspend_old"
+	reissueStepDelete    = "delete_old"
+	reissueStepRollback  = "rollback"
+)
+
+// reissueRollbackTimeout bounds the rollback of a replacement card, which
+// runs even if the caller's context has been cancelled
+const reissueRollbackTimeout = 30 * time.Second
+
+// Reissue replaces a card with a newly provisioned copy and retires the
+// original. The replacement carries over the original card's attributes
+// and metadata with any overrides applied. If retiring the original fails,
+// the replacement is deleted so that only one live card remains; the
+// rollback runs even if ctx has been cancelled.
+//
+// The returned report lists every step attempted, including on failure.
+func (s *AccessCardsService) Reissue(ctx context.Context, cardID string, overrides models.ReissueParams) (*models.ReissueReport, error) {
+	report := &models.ReissueReport{OldCardID: cardID}
+
+	existing, err := s.Get(ctx, cardID)
+	report.Steps = append(report.Steps, reissueStep(reissueStepRead, cardID, err))
+	if err != nil {
+		return report, fmt.Errorf("error reissuing card: %w", err)
+	}
+
+	old, ok := models.AsCard(existing)
+	if !ok {
+		err := fmt.Errorf("card %s is a unified access pass; reissue its child cards individually", cardID)
+		report.Steps[len(report.Steps)-1].Error = err.Error()
+		return report, fmt.Errorf("error reissuing card: %w", err)
+	}
+
+	replacement, err := s.Provision(ctx, reissueParams(old, overrides))
+	var newCardID string
+	if replacement != nil {
+		newCardID = replacement.GetID()
+	}
+	report.Steps = append(report.Steps, reissueStep(reissueStepProvision, newCardID, err))
+	if err != nil {
+		return report, fmt.Errorf("error reissuing card: %w", err)
+	}
+	report.NewCard = replacement
+
+	retire, retireStep := s.Delete, reissueStepDelete
+	if overrides.SuspendOld {
+		retire, retireStep = s.Suspend, reissueStepSuspend
+	}
+
+	err = retire(ctx, cardID)
+	report.Steps = append(report.Steps, reissueStep(retireStep, cardID, err))
+	if err != nil {
+		// The retire step may have failed because ctx was cancelled, so
+		// the rollback gets its own deadline
+		rollbackCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), reissueRollbackTimeout)
+		defer cancel()
+		rollbackErr := s.DeleteAll(rollbackCtx, replacement)
+		report.Steps = append(report.Steps, reissueStep(reissueStepRollback, newCardID, rollbackErr))
+		if rollbackErr != nil {
+			return report, fmt.Errorf("error reissuing card: %w", errors.Join(err, fmt.Errorf("error rolling back %s: %w", newCardID, rollbackErr)))
+		}
+		report.RolledBack = true
+		report.NewCard = nil
+		return report, fmt.Errorf("error reissuing card: %w", err)
+	}
+
+	return report, nil
+}
+
+// reissueParams builds provisioning parameters from an existing card
+func reissueParams(card *models.Card, overrides models.ReissueParams) models.ProvisionParams {
+	params := models.ProvisionParams{
+		CardTemplateID: card.CardTemplateID,
+		EmployeeID:     card.EmployeeID,
+		CardNumber:     card.CardNumber,
+		SiteCode:       card.SiteCode,
+		FullName:       card.FullName,
+		Email:          card.Email,
+		PhoneNumber:    card.PhoneNumber,
+		Classification: card.Classification,
+		StartDate:      card.StartDate,
+		ExpirationDate: card.ExpirationDate,
+		EmployeePhoto:  card.EmployeePhoto,
+		Metadata:       card.Metadata,
+	}
+
+	if overrides.CardTemplateID != "" {
+		params.CardTemplateID = overrides.CardTemplateID
+	}
+	if overrides.CardNumber != "" {
+		params.CardNumber = overrides.CardNumber
+	}
+	if overrides.SiteCode != "" {
+		params.SiteCode = overrides.SiteCode
+	}
+	if overrides.StartDate != nil {
+		params.StartDate = *overrides.StartDate
+	}
+	if overrides.ExpirationDate != nil {
+		params.ExpirationDate = *overrides.ExpirationDate
+	}
+
+	return params
+}
+
+func reissueStep(name, cardID string, err error) models.ReissueStep {
+	step := models.ReissueStep{Name: name, CardID: cardID}
+	if err != nil {
+		step.Error = err.Error()
+	}
+	return step
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/Access-Grid/accessgrid-go/client"
+	"github.com/Access-Grid/accessgrid-go/models"
+)
+
+func setupReissueTestServer(failDeleteOld bool) (*httptest.Server, *AccessCardsService, *models.ProvisionParams, *sync.Map) {
+	provisioned := &models.ProvisionParams{}
+	calls := &sync.Map{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		calls.Store(r.URL.Path, true)
+
+		switch r.URL.Path {
+		case "/v1/key-cards/0xc4rd1d":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{
+				"id": "0xc4rd1d",
+				"card_template_id": "0xd3adb00b5",
+				"employee_id": "123456789",
+				"card_number": "12345",
+				"full_name": "Employee name",
+				"email": "employee@example.com",
+				"state": "active",
+				"metadata": {"department": "engineering"}
+			}`))
+		case "/v1/key-cards":
+			json.NewDecoder(r.Body).Decode(provisioned)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "0xn3wc4rd", "card_template_id": "0xd3adb00b5", "state": "active"}`))
+		case "/v1/key-cards/0xc4rd1d/delete":
+			if failDeleteOld {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"message": "delete failed"}`))
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{}`))
+		case "/v1/key-cards/0xc4rd1d/suspend", "/v1/key-cards/0xn3wc4rd/delete":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	c, _ := client.NewClient("test-account", "test-secret", client.WithBaseURL(server.URL))
+	service := NewAccessCardsService(c)
+
+	return server, service, provisioned, calls
+}
+
+func TestAccessCardsService_Reissue(t *testing.T) {
+	server, service, provisioned, calls := setupReissueTestServer(false)
+	defer server.Close()
+
+	report, err := service.Reissue(context.Background(), "0xc4rd1d", models.ReissueParams{CardNumber: "54321"})
+	if err != nil {
+		t.Fatalf("Reissue() error = %v", err)
+	}
+
+	if report.NewCard == nil || report.NewCard.GetID() != "0xn3wc4rd" {
+		t.Errorf("Reissue() report.NewCard = %v, want 0xn3wc4rd", report.NewCard)
+	}
+	if len(report.Steps) != 3 {
+		t.Fatalf("Reissue() got %v steps, want %v", len(report.Steps), 3)
+	}
+	if report.Steps[2].Name != "delete_old" {
+		t.Errorf("Reissue() report.Steps[2].Name = %v, want %v", report.Steps[2].Name, "delete_old")
+	}
+	if provisioned.CardNumber != "54321" {
+		t.Errorf("Reissue() provisioned CardNumber = %v, want %v", provisioned.CardNumber, "54321")
+	}
+	if provisioned.EmployeeID != "123456789" {
+		t.Errorf("Reissue() provisioned EmployeeID = %v, want %v", provisioned.EmployeeID, "123456789")
+	}
+	if provisioned.Metadata["department"] != "engineering" {
+		t.Errorf("Reissue() provisioned Metadata = %v, want carried over", provisioned.Metadata)
+	}
+	if _, ok := calls.Load("/v1/key-cards/0xn3wc4rd/delete"); ok {
+		t.Error("Reissue() unexpectedly deleted the replacement card")
+	}
+}
+
+func TestAccessCardsService_ReissueSuspendOld(t *testing.T) {
+	server, service, _, calls := setupReissueTestServer(false)
+	defer server.Close()
+
+	_, err := service.Reissue(context.Background(), "0xc4rd1d", models.ReissueParams{SuspendOld: true})
+	if err != nil {
+		t.Fatalf("Reissue() error = %v", err)
+	}
+
+	if _, ok := calls.Load("/v1/key-cards/0xc4rd1d/suspend"); !ok {
+		t.Error("Reissue() expected the old card to be suspended")
+	}
+	if _, ok := calls.Load("/v1/key-cards/0xc4rd1d/delete"); ok {
+		t.Error("Reissue() unexpectedly deleted the old card")
+	}
+}
+
+func TestAccessCardsService_ReissueRollback(t *testing.T) {
+	server, service, _, calls := setupReissueTestServer(true)
+	defer server.Close()
+
+	report, err := service.Reissue(context.Background(), "0xc4rd1d", models.ReissueParams{})
+	if err == nil {
+		t.Fatal("Reissue() expected error")
+	}
+
+	if !report.RolledBack {
+		t.Error("Reissue() expected report.RolledBack")
+	}
+	if report.NewCard != nil {
+		t.Errorf("Reissue() report.NewCard = %v, want nil after rollback", report.NewCard)
+	}
+	if _, ok := calls.Load("/v1/key-cards/0xn3wc4rd/delete"); !ok {
+		t.Error("Reissue() expected the replacement card to be deleted")
+	}
+	last := report.Steps[len(report.Steps)-1]
+	if last.Name != "rollback" || last.Error != "" {
+		t.Errorf("Reissue() last step = %+v, want successful rollback", last)
+	}
+}
+
+func TestAccessCardsService_ReissueRollbackAfterCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := &sync.Map{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/key-cards/0xc4rd1d":
+			w.Write([]byte(`{"id": "0xc4rd1d", "card_template_id": "0xd3adb00b5", "state": "active"}`))
+		case "/v1/key-cards":
+			w.Write([]byte(`{"id": "0xn3wc4rd", "card_template_id": "0xd3adb00b5", "state": "active"}`))
+		case "/v1/key-cards/0xc4rd1d/delete":
+			// The caller gives up while the old card is being retired
+			cancel()
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"message": "delete failed"}`))
+		case "/v1/key-cards/0xn3wc4rd/delete":
+			calls.Store(r.URL.Path, true)
+			w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c, _ := client.NewClient("test-account", "test-secret", client.WithBaseURL(server.URL))
+	report, err := NewAccessCardsService(c).Reissue(ctx, "0xc4rd1d", models.ReissueParams{})
+	if err == nil {
+		t.Fatal("Reissue() expected error")
+	}
+	_, deleted := calls.Load("/v1/key-cards/0xn3wc4rd/delete")
+	if !report.RolledBack || !deleted {
+		t.Errorf("Reissue() after cancel rolled back = %v, replacement deleted = %v, want both", report.RolledBack, deleted)
+	}
+}
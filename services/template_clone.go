@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Access-Grid/accessgrid-go/models"
+)
+
+// maxTemplateImageSize caps how much of a remote image CloneTemplate will
+// download
+const maxTemplateImageSize = 10 << 20
+
+// CloneTemplate creates a copy of a template in the same account with the
+// given overrides applied. See CloneTemplateTo.
+func (s *ConsoleService) CloneTemplate(ctx context.Context, templateID string, overrides models.CloneTemplateParams) (*models.CloneTemplateResult, error) {
+	return s.CloneTemplateTo(ctx, s, templateID, overrides)
+}
+
+// CloneTemplateTo reads a template and creates a copy of it through dest,
+// which may belong to a different account. Images the API returns as URLs
+// are downloaded and re-encoded so they carry over between accounts. Fields
+// that cannot be copied, such as device counts on a platform other than
+// Apple or images that fail to download, are left empty and listed in the
+// result's Skipped. An inherited protocol the target platform doesn't
+// support is replaced with the platform's first supported protocol and
+// also listed; set overrides.Protocol to choose it.
+func (s *ConsoleService) CloneTemplateTo(ctx context.Context, dest *ConsoleService, templateID string, overrides models.CloneTemplateParams) (*models.CloneTemplateResult, error) {
+	if dest == nil {
+		return nil, errors.New("destination console service is required")
+	}
+
+	source, err := s.ReadTemplate(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	params := models.CreateTemplateParams{
+		Name:        source.Name,
+		Platform:    source.Platform,
+		UseCase:     source.UseCase,
+		Protocol:    source.Protocol,
+		WatchCount:  source.WatchCount,
+		IPhoneCount: source.IPhoneCount,
+		Design:      source.Design,
+		SupportInfo: source.SupportInfo,
+	}
+	if overrides.Name != "" {
+		params.Name = overrides.Name
+	}
+	if overrides.Platform != "" {
+		params.Platform = overrides.Platform
+	}
+	if overrides.UseCase != "" {
+		params.UseCase = overrides.UseCase
+	}
+	if overrides.Protocol != "" {
+		params.Protocol = overrides.Protocol
+	}
+	if overrides.WatchCount != nil {
+		params.WatchCount = *overrides.WatchCount
+	}
+	if overrides.IPhoneCount != nil {
+		params.IPhoneCount = *overrides.IPhoneCount
+	}
+
+	result := &models.CloneTemplateResult{SourceID: source.ID}
+	skip := func(field, reason string) {
+		result.Skipped = append(result.Skipped, models.SkippedField{Field: field, Reason: reason})
+	}
+
+	// An inherited protocol the new platform doesn't support is swapped for
+	// one it does, as happens when making a Google copy of an Apple/SEOS
+	// template. An explicit override is left for validation to reject.
+	if overrides.Protocol == "" && params.Platform.Valid() && !params.Platform.Supports(params.Protocol) {
+		if supported := params.Platform.Protocols(); len(supported) > 0 {
+			skip("protocol", fmt.Sprintf("%q not supported on platform %q; using %q", params.Protocol, params.Platform, supported[0]))
+			params.Protocol = supported[0]
+		}
+	}
+
+	// Device counts only apply on some platforms; drop inherited ones
+	// rather than have the API reject the copy
+	if !params.Platform.SupportsDeviceCounts() {
+		if overrides.WatchCount == nil && params.WatchCount != 0 {
+			skip("watch_count", fmt.Sprintf("not supported on platform %q", params.Platform))
+			params.WatchCount = 0
+		}
+		if overrides.IPhoneCount == nil && params.IPhoneCount != 0 {
+			skip("iphone_count", fmt.Sprintf("not supported on platform %q", params.Platform))
+			params.IPhoneCount = 0
+		}
+	}
+
+	images := []struct {
+		field string
+		value *string
+	}{
+		{"design.background_image", &params.Design.BackgroundImage},
+		{"design.logo_image", &params.Design.LogoImage},
+		{"design.icon_image", &params.Design.IconImage},
+	}
+	for _, image := range images {
+		encoded, err := s.encodeTemplateImage(ctx, *image.value)
+		if err != nil {
+			skip(image.field, err.Error())
+		}
+		*image.value = encoded
+	}
+
+	template, err := dest.CreateTemplate(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("error cloning template %s: %w", templateID, err)
+	}
+	result.Template = template
+	return result, nil
+}
+
+// encodeTemplateImage converts an image as returned by ReadTemplate into the
+// base64 form CreateTemplate expects. Remote URLs are downloaded, data URIs
+// are unwrapped and anything else is assumed to be encoded already. On
+// error it returns an empty string.
+func (s *ConsoleService) encodeTemplateImage(ctx context.Context, image string) (string, error) {
+	switch {
+	case image == "":
+		return "", nil
+	case strings.HasPrefix(image, "data:"):
+		_, data, ok := strings.Cut(image, ";base64,")
+		if !ok {
+			return "", errors.New("data URI is not base64 encoded")
+		}
+		return data, nil
+	case strings.HasPrefix(image, "http://"), strings.HasPrefix(image, "https://"):
+		data, err := s.downloadImage(ctx, image)
+		if err != nil {
+			return "", err
+		}
+		return base64.StdEncoding.EncodeToString(data), nil
+	default:
+		return image, nil
+	}
+}
+
+func (s *ConsoleService) downloadImage(ctx context.Context, imageURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating image request: %w", err)
+	}
+
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error downloading image: status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxTemplateImageSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("error downloading image: %w", err)
+	}
+	if len(data) > maxTemplateImageSize {
+		return nil, fmt.Errorf("image exceeds %d bytes", maxTemplateImageSize)
+	}
+	return data, nil
+}
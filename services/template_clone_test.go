@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Access-Grid/accessgrid-go/client"
+	"github.com/Access-Grid/accessgrid-go/models"
+)
+
+func setupCloneTestServers(t *testing.T) (source, dest *ConsoleService, created *models.CreateTemplateParams) {
+	t.Helper()
+	created = &models.CreateTemplateParams{}
+
+	var sourceURL string
+	sourceServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/console/card-templates/0xd3adb00b5":
+			json.NewEncoder(w).Encode(models.Template{
+				ID:          "0xd3adb00b5",
+				Name:        "Employee NFC key",
+				Platform:    "apple",
+				UseCase:     "employee_badge",
+				Protocol:    "desfire",
+				WatchCount:  2,
+				IPhoneCount: 3,
+				Design: models.TemplateDesign{
+					BackgroundColor: "#FFFFFF",
+					BackgroundImage: "data:image/png;base64,YmFja2dyb3VuZA==",
+					LogoImage:       sourceURL + "/images/logo.png",
+					IconImage:       sourceURL + "/images/missing.png",
+				},
+				SupportInfo: models.SupportInfo{
+					SupportURL:            "https://help.example.com",
+					SupportEmail:          "support@example.com",
+					PrivacyPolicyURL:      "https://example.com/privacy",
+					TermsAndConditionsURL: "https://example.com/terms",
+				},
+			})
+		case "/v1/console/card-templates/0x5e05":
+			json.NewEncoder(w).Encode(models.Template{
+				ID:       "0x5e05",
+				Name:     "Employee SEOS key",
+				Platform: "apple",
+				UseCase:  "employee_badge",
+				Protocol: "seos",
+				SupportInfo: models.SupportInfo{
+					SupportURL:            "https://help.example.com",
+					PrivacyPolicyURL:      "https://example.com/privacy",
+					TermsAndConditionsURL: "https://example.com/terms",
+				},
+			})
+		case "/images/logo.png":
+			w.Write([]byte("logo"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "not found"}`))
+		}
+	}))
+	t.Cleanup(sourceServer.Close)
+	sourceURL = sourceServer.URL
+
+	destServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/console/card-templates" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(created)
+		json.NewEncoder(w).Encode(models.Template{ID: "0xc10ne", Name: created.Name, Platform: created.Platform})
+	}))
+	t.Cleanup(destServer.Close)
+
+	sourceClient, _ := client.NewClient("source-account", "source-secret", client.WithBaseURL(sourceServer.URL))
+	destClient, _ := client.NewClient("dest-account", "dest-secret", client.WithBaseURL(destServer.URL))
+	return NewConsoleService(sourceClient), NewConsoleService(destClient), created
+}
+
+func TestConsoleService_CloneTemplateTo(t *testing.T) {
+	source, dest, created := setupCloneTestServers(t)
+
+	result, err := source.CloneTemplateTo(context.Background(), dest, "0xd3adb00b5", models.CloneTemplateParams{
+		Name:     "Employee NFC key (Android)",
+		Platform: "android",
+	})
+	if err != nil {
+		t.Fatalf("CloneTemplateTo() error = %v", err)
+	}
+
+	if result.SourceID != "0xd3adb00b5" || result.Template.ID != "0xc10ne" {
+		t.Errorf("CloneTemplateTo() result = %+v", result)
+	}
+	if created.Name != "Employee NFC key (Android)" || created.Platform != "android" || created.Protocol != "desfire" {
+		t.Errorf("created params = %+v", created)
+	}
+	if created.WatchCount != 0 || created.IPhoneCount != 0 {
+		t.Errorf("created counts = %d/%d, want 0/0 on android", created.WatchCount, created.IPhoneCount)
+	}
+	if created.SupportInfo.SupportEmail != "support@example.com" || created.Design.BackgroundColor != "#FFFFFF" {
+		t.Errorf("created design/support = %+v / %+v", created.Design, created.SupportInfo)
+	}
+	if created.Design.BackgroundImage != "YmFja2dyb3VuZA==" {
+		t.Errorf("background image = %q, want data URI payload", created.Design.BackgroundImage)
+	}
+	if created.Design.LogoImage != base64.StdEncoding.EncodeToString([]byte("logo")) {
+		t.Errorf("logo image = %q, want downloaded and encoded", created.Design.LogoImage)
+	}
+	if created.Design.IconImage != "" {
+		t.Errorf("icon image = %q, want empty after failed download", created.Design.IconImage)
+	}
+
+	skipped := map[string]bool{}
+	for _, s := range result.Skipped {
+		skipped[s.Field] = true
+	}
+	for _, field := range []string{"watch_count", "iphone_count", "design.icon_image"} {
+		if !skipped[field] {
+			t.Errorf("Skipped = %+v, missing %s", result.Skipped, field)
+		}
+	}
+}
+
+func TestConsoleService_CloneTemplate_KeepsAppleCounts(t *testing.T) {
+	source, dest, created := setupCloneTestServers(t)
+
+	watchCount := 1
+	result, err := source.CloneTemplateTo(context.Background(), dest, "0xd3adb00b5", models.CloneTemplateParams{WatchCount: &watchCount})
+	if err != nil {
+		t.Fatalf("CloneTemplateTo() error = %v", err)
+	}
+	if created.Name != "Employee NFC key" || created.WatchCount != 1 || created.IPhoneCount != 3 {
+		t.Errorf("created params = %+v", created)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0].Field != "design.icon_image" {
+		t.Errorf("Skipped = %+v, want only the missing icon", result.Skipped)
+	}
+}
+
+func TestConsoleService_CloneTemplate_UnsupportedProtocol(t *testing.T) {
+	source, dest, created := setupCloneTestServers(t)
+
+	result, err := source.CloneTemplateTo(context.Background(), dest, "0x5e05", models.CloneTemplateParams{Platform: "google"})
+	if err != nil {
+		t.Fatalf("CloneTemplateTo() error = %v", err)
+	}
+	if created.Platform != "google" || created.Protocol != models.ProtocolDESFire {
+		t.Errorf("created params = %+v, want google/desfire", created)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0].Field != "protocol" {
+		t.Errorf("Skipped = %+v, want the protocol change", result.Skipped)
+	}
+
+	// An explicit but unsupported protocol is rejected, not replaced
+	_, err = source.CloneTemplateTo(context.Background(), dest, "0x5e05", models.CloneTemplateParams{Platform: "google", Protocol: "seos"})
+	var verr *models.ValidationError
+	if !errors.As(err, &verr) || !verr.HasField("protocol") {
+		t.Errorf("CloneTemplateTo() error = %v, want protocol validation error", err)
+	}
+}
+
+func TestConsoleService_CloneTemplate_NotFound(t *testing.T) {
+	source, _, _ := setupCloneTestServers(t)
+
+	_, err := source.CloneTemplate(context.Background(), "0xmissing", models.CloneTemplateParams{})
+	if !client.IsNotFound(err) {
+		t.Errorf("CloneTemplate() error = %v, want not found", err)
+	}
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/Access-Grid/accessgrid-go/models"
+)
+
+// templateDeletionConcurrency is the number of cards migrated or suspended
+// in parallel by SafeDeleteTemplate
+const templateDeletionConcurrency = 4
+
+// ErrTemplateHasActiveCards is returned by SafeDeleteTemplate when active
+// cards would remain on a template and deletion was not forced
+var ErrTemplateHasActiveCards = errors.New("template has active cards")
+
+// SafeDeleteTemplate deletes a template only once no active cards depend on
+// it. Cards issued from the template are counted per state; active cards
+// can first be migrated to another template or suspended, as set in
+// params. If active cards remain afterwards, the template is kept and
+// ErrTemplateHasActiveCards is returned unless params.Force is set.
+//
+// With params.DryRun nothing is changed and the report lists what would be
+// done. The migration target, if any, must exist and be on the same
+// platform; this is checked up front, including on a dry run. Once the
+// template has been read, the report is returned even when an error
+// occurs; use WriteTemplateDeletionSummary to print it.
+func (s *ConsoleService) SafeDeleteTemplate(ctx context.Context, templateID string, params models.DeleteTemplateParams) (*models.TemplateDeletionReport, error) {
+	template, err := s.ReadTemplate(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	report := &models.TemplateDeletionReport{
+		TemplateID:   templateID,
+		TemplateName: template.Name,
+		CardCounts:   make(map[string]int),
+		DryRun:       params.DryRun,
+	}
+
+	// Check the migration target before changing anything, so a dry run
+	// catches a missing or incompatible template too
+	if params.MigrateToTemplateID != "" {
+		if params.MigrateToTemplateID == templateID {
+			return report, errors.New("cannot migrate cards to the template being deleted")
+		}
+		target, err := s.ReadTemplate(ctx, params.MigrateToTemplateID)
+		if err != nil {
+			return report, fmt.Errorf("error reading migration target: %w", err)
+		}
+		if target.Platform.Canonical() != template.Platform.Canonical() {
+			return report, fmt.Errorf("cannot migrate cards from a %s template to %s template %s", template.Platform, target.Platform, target.ID)
+		}
+	}
+
+	cards := NewAccessCardsService(s.client)
+	listed, err := cards.List(ctx, &models.ListKeysParams{TemplateID: templateID})
+	if err != nil {
+		return report, fmt.Errorf("error deleting template: %w", err)
+	}
+
+	var active []models.Card
+	for _, card := range listed {
+		if card.CardTemplateID != templateID {
+			continue
+		}
+		report.CardCounts[card.State]++
+		if card.State == models.CardStateActive {
+			active = append(active, card)
+		}
+	}
+
+	action := ""
+	switch {
+	case params.MigrateToTemplateID != "":
+		action = models.TemplateDeletionMigrate
+	case params.SuspendActive:
+		action = models.TemplateDeletionSuspend
+	}
+
+	report.RemainingActive = len(active)
+	if action != "" {
+		report.Actions = make([]models.TemplateDeletionAction, len(active))
+		for i, card := range active {
+			report.Actions[i] = models.TemplateDeletionAction{CardID: card.ID, Action: action}
+		}
+		if params.DryRun {
+			report.RemainingActive = 0
+		} else {
+			report.RemainingActive = s.applyTemplateDeletionActions(ctx, cards, report.Actions, params)
+		}
+	}
+
+	if params.DryRun {
+		return report, nil
+	}
+	if report.RemainingActive > 0 && !params.Force {
+		return report, fmt.Errorf("%w: %d active cards remain on template %s", ErrTemplateHasActiveCards, report.RemainingActive, templateID)
+	}
+
+	if err := s.DeleteTemplate(ctx, templateID); err != nil {
+		return report, err
+	}
+	report.Deleted = true
+	return report, nil
+}
+
+// applyTemplateDeletionActions migrates or suspends each card in actions,
+// recording the outcome in place, and returns the number that failed
+func (s *ConsoleService) applyTemplateDeletionActions(ctx context.Context, cards *AccessCardsService, actions []models.TemplateDeletionAction, params models.DeleteTemplateParams) int {
+	sem := make(chan struct{}, templateDeletionConcurrency)
+	var wg sync.WaitGroup
+	for i := range actions {
+		wg.Add(1)
+		go func(a *models.TemplateDeletionAction) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			var err error
+			switch a.Action {
+			case models.TemplateDeletionMigrate:
+				var reissue *models.ReissueReport
+				reissue, err = cards.Reissue(ctx, a.CardID, models.ReissueParams{CardTemplateID: params.MigrateToTemplateID})
+				if err == nil && reissue.NewCard != nil {
+					a.NewCardID = reissue.NewCard.GetID()
+				}
+			case models.TemplateDeletionSuspend:
+				err = cards.Suspend(ctx, a.CardID)
+			}
+			if err != nil {
+				a.Error = err.Error()
+			}
+		}(&actions[i])
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, a := range actions {
+		if a.Error != "" {
+			failed++
+		}
+	}
+	return failed
+}
+
+// WriteTemplateDeletionSummary prints the cards affected by a template
+// deletion and what was done to them
+func WriteTemplateDeletionSummary(w io.Writer, r *models.TemplateDeletionReport) error {
+	fmt.Fprintf(w, "Template %s (%s)\n", r.TemplateID, r.TemplateName)
+
+	states := make([]string, 0, len(r.CardCounts))
+	for state := range r.CardCounts {
+		states = append(states, state)
+	}
+	slices.Sort(states)
+	counts := make([]string, 0, len(states))
+	for _, state := range states {
+		counts = append(counts, fmt.Sprintf("%d %s", r.CardCounts[state], state))
+	}
+	if len(counts) == 0 {
+		counts = append(counts, "none")
+	}
+	fmt.Fprintf(w, "Cards: %s\n", strings.Join(counts, ", "))
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, a := range r.Actions {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", a.Action, a.CardID, a.NewCardID, a.Error)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	switch {
+	case r.DryRun:
+		_, err := fmt.Fprintf(w, "Dry run: %d active cards would remain; template not deleted\n", r.RemainingActive)
+		return err
+	case r.Deleted:
+		_, err := fmt.Fprintf(w, "Template deleted with %d active cards remaining\n", r.RemainingActive)
+		return err
+	default:
+		_, err := fmt.Fprintf(w, "Template not deleted: %d active cards remain\n", r.RemainingActive)
+		return err
+	}
+}
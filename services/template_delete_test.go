@@ -0,0 +1,188 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Access-Grid/accessgrid-go/client"
+	"github.com/Access-Grid/accessgrid-go/models"
+)
+
+// deletionBackend serves a template with cards in several states and
+// records every change made
+type deletionBackend struct {
+	mu          sync.Mutex
+	failSuspend string
+	calls       []string
+	provisioned []string
+}
+
+func (b *deletionBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	cards := map[string]string{
+		"0xact1":  `{"id": "0xact1", "card_template_id": "0xd3adb00b5", "employee_id": "1", "state": "active"}`,
+		"0xact2":  `{"id": "0xact2", "card_template_id": "0xd3adb00b5", "employee_id": "2", "state": "active"}`,
+		"0xsusp":  `{"id": "0xsusp", "card_template_id": "0xd3adb00b5", "employee_id": "3", "state": "suspended"}`,
+		"0x0th3r": `{"id": "0x0th3r", "card_template_id": "0xp4rk1ng", "employee_id": "4", "state": "active"}`,
+	}
+
+	switch path := r.URL.Path; {
+	case path == "/v1/console/card-templates/0xd3adb00b5" && r.Method == http.MethodGet:
+		w.Write([]byte(`{"id": "0xd3adb00b5", "name": "Employee NFC key", "platform": "apple"}`))
+	case path == "/v1/console/card-templates/0xn3wt3mpl" && r.Method == http.MethodGet:
+		w.Write([]byte(`{"id": "0xn3wt3mpl", "name": "Employee NFC key v2", "platform": "apple"}`))
+	case path == "/v1/console/card-templates/0xg00gl3" && r.Method == http.MethodGet:
+		w.Write([]byte(`{"id": "0xg00gl3", "name": "Employee NFC key (Google)", "platform": "google"}`))
+	case path == "/v1/console/card-templates/0xd3adb00b5" && r.Method == http.MethodDelete:
+		b.calls = append(b.calls, "delete_template")
+		w.WriteHeader(http.StatusNoContent)
+	case path == "/v1/key-cards" && r.Method == http.MethodGet:
+		w.Write([]byte(`{"keys": [` + cards["0xact1"] + `,` + cards["0xact2"] + `,` + cards["0xsusp"] + `,` + cards["0x0th3r"] + `]}`))
+	case path == "/v1/key-cards" && r.Method == http.MethodPost:
+		var params models.ProvisionParams
+		json.NewDecoder(r.Body).Decode(&params)
+		b.provisioned = append(b.provisioned, params.CardTemplateID)
+		w.Write([]byte(`{"id": "0xn3w` + params.EmployeeID + `", "state": "active"}`))
+	case strings.HasSuffix(path, "/suspend"), strings.HasSuffix(path, "/delete"):
+		id := strings.Split(path, "/")[3]
+		if strings.HasSuffix(path, "/suspend") && id == b.failSuspend {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"message": "suspend failed"}`))
+			return
+		}
+		b.calls = append(b.calls, strings.TrimPrefix(path, "/v1/key-cards/"))
+		w.Write([]byte(`{}`))
+	default:
+		if card, ok := cards[strings.TrimPrefix(path, "/v1/key-cards/")]; ok {
+			w.Write([]byte(card))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message": "not found"}`))
+	}
+}
+
+func setupTemplateDeleteTestServer(t *testing.T) (*deletionBackend, *ConsoleService) {
+	t.Helper()
+	backend := &deletionBackend{}
+	server := httptest.NewServer(backend)
+	t.Cleanup(server.Close)
+
+	c, _ := client.NewClient("test-account", "test-secret", client.WithBaseURL(server.URL))
+	return backend, NewConsoleService(c)
+}
+
+func TestConsoleService_SafeDeleteTemplate_RefusesActiveCards(t *testing.T) {
+	backend, service := setupTemplateDeleteTestServer(t)
+
+	report, err := service.SafeDeleteTemplate(context.Background(), "0xd3adb00b5", models.DeleteTemplateParams{})
+	if !errors.Is(err, ErrTemplateHasActiveCards) {
+		t.Fatalf("SafeDeleteTemplate() error = %v, want ErrTemplateHasActiveCards", err)
+	}
+	if report.CardCounts[models.CardStateActive] != 2 || report.CardCounts[models.CardStateSuspended] != 1 || report.RemainingActive != 2 {
+		t.Errorf("SafeDeleteTemplate() report = %+v", report)
+	}
+	if report.Deleted || len(backend.calls) != 0 {
+		t.Errorf("SafeDeleteTemplate() made changes: %v", backend.calls)
+	}
+
+	report, err = service.SafeDeleteTemplate(context.Background(), "0xd3adb00b5", models.DeleteTemplateParams{Force: true})
+	if err != nil || !report.Deleted {
+		t.Errorf("SafeDeleteTemplate(Force) = %+v, %v", report, err)
+	}
+}
+
+func TestConsoleService_SafeDeleteTemplate_Migrate(t *testing.T) {
+	backend, service := setupTemplateDeleteTestServer(t)
+
+	report, err := service.SafeDeleteTemplate(context.Background(), "0xd3adb00b5", models.DeleteTemplateParams{MigrateToTemplateID: "0xn3wt3mpl"})
+	if err != nil {
+		t.Fatalf("SafeDeleteTemplate() error = %v", err)
+	}
+	if !report.Deleted || report.RemainingActive != 0 || len(report.Actions) != 2 {
+		t.Fatalf("SafeDeleteTemplate() report = %+v", report)
+	}
+	for _, a := range report.Actions {
+		if a.Action != models.TemplateDeletionMigrate || !strings.HasPrefix(a.NewCardID, "0xn3w") || a.Error != "" {
+			t.Errorf("action = %+v", a)
+		}
+	}
+	if len(backend.provisioned) != 2 || backend.provisioned[0] != "0xn3wt3mpl" {
+		t.Errorf("provisioned on = %v, want the new template", backend.provisioned)
+	}
+	if backend.calls[len(backend.calls)-1] != "delete_template" {
+		t.Errorf("calls = %v, want template deleted last", backend.calls)
+	}
+}
+
+func TestConsoleService_SafeDeleteTemplate_SuspendFailure(t *testing.T) {
+	backend, service := setupTemplateDeleteTestServer(t)
+	backend.failSuspend = "0xact2"
+
+	report, err := service.SafeDeleteTemplate(context.Background(), "0xd3adb00b5", models.DeleteTemplateParams{SuspendActive: true})
+	if !errors.Is(err, ErrTemplateHasActiveCards) {
+		t.Fatalf("SafeDeleteTemplate() error = %v, want ErrTemplateHasActiveCards", err)
+	}
+	if report.RemainingActive != 1 || report.Deleted {
+		t.Errorf("SafeDeleteTemplate() report = %+v", report)
+	}
+
+	var out bytes.Buffer
+	if err := WriteTemplateDeletionSummary(&out, report); err != nil {
+		t.Fatalf("WriteTemplateDeletionSummary() error = %v", err)
+	}
+	for _, want := range []string{"Employee NFC key", "2 active, 1 suspended", "suspend failed", "not deleted: 1 active"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("WriteTemplateDeletionSummary() = %q, missing %q", out.String(), want)
+		}
+	}
+}
+
+func TestConsoleService_SafeDeleteTemplate_DryRun(t *testing.T) {
+	backend, service := setupTemplateDeleteTestServer(t)
+
+	report, err := service.SafeDeleteTemplate(context.Background(), "0xd3adb00b5", models.DeleteTemplateParams{SuspendActive: true, DryRun: true})
+	if err != nil {
+		t.Fatalf("SafeDeleteTemplate() error = %v", err)
+	}
+	if len(report.Actions) != 2 || report.Deleted || len(backend.calls) != 0 {
+		t.Errorf("SafeDeleteTemplate(DryRun) = %+v, calls %v", report, backend.calls)
+	}
+}
+
+func TestConsoleService_SafeDeleteTemplate_ChecksMigrationTarget(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+	}{
+		{"Missing", "0xm1ss1ng"},
+		{"Other platform", "0xg00gl3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend, service := setupTemplateDeleteTestServer(t)
+
+			params := models.DeleteTemplateParams{MigrateToTemplateID: tt.target, DryRun: true}
+			if _, err := service.SafeDeleteTemplate(context.Background(), "0xd3adb00b5", params); err == nil {
+				t.Error("SafeDeleteTemplate(DryRun) expected error")
+			}
+			params.DryRun = false
+			if _, err := service.SafeDeleteTemplate(context.Background(), "0xd3adb00b5", params); err == nil {
+				t.Error("SafeDeleteTemplate() expected error")
+			}
+			if len(backend.calls) != 0 || len(backend.provisioned) != 0 {
+				t.Errorf("SafeDeleteTemplate() made changes: %v, provisioned %v", backend.calls, backend.provisioned)
+			}
+		})
+	}
+}
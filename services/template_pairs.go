@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/Access-Grid/accessgrid-go/models"
+)
+
+// CreateTemplatePair links an Apple template and a Google template. Both
+// templates are read first and must exist on the expected platforms.
+func (s *ConsoleService) CreateTemplatePair(ctx context.Context, params models.CreateTemplatePairParams) (*models.TemplatePair, error) {
+	members := []struct {
+		id       string
+		platform models.TemplatePlatform
+	}{
+		{params.AppleCardTemplateID, models.TemplatePlatformApple},
+		{params.GoogleCardTemplateID, models.TemplatePlatformGoogle},
+	}
+	for _, m := range members {
+		if m.id == "" {
+			return nil, fmt.Errorf("a %s template is required", m.platform)
+		}
+		template, err := s.ReadTemplate(ctx, m.id)
+		if err != nil {
+			return nil, err
+		}
+		if template.Platform.Canonical() != m.platform {
+			return nil, fmt.Errorf("template %s is on platform %q, want %q", m.id, template.Platform, m.platform)
+		}
+	}
+
+	var pair models.TemplatePair
+	err := s.client.Request(ctx, http.MethodPost, "/v1/console/card-template-pairs", params, &pair)
+	if err != nil {
+		return nil, fmt.Errorf("error creating template pair: %w", err)
+	}
+	return &pair, nil
+}
+
+// ReadTemplatePair retrieves a template pair by ID
+func (s *ConsoleService) ReadTemplatePair(ctx context.Context, pairID string) (*models.TemplatePair, error) {
+	var pair models.TemplatePair
+	path := fmt.Sprintf("/v1/console/card-template-pairs/%s", url.PathEscape(pairID))
+	err := s.client.Request(ctx, http.MethodGet, path, nil, &pair)
+	if err != nil {
+		return nil, fmt.Errorf("error reading template pair: %w", err)
+	}
+	return &pair, nil
+}
+
+// ListTemplatePairs retrieves all template pairs
+func (s *ConsoleService) ListTemplatePairs(ctx context.Context) ([]models.TemplatePair, error) {
+	var pairs []models.TemplatePair
+	err := s.client.Request(ctx, http.MethodGet, "/v1/console/card-template-pairs", nil, &pairs)
+	if err != nil {
+		return nil, fmt.Errorf("error listing template pairs: %w", err)
+	}
+	return pairs, nil
+}
+
+// DeleteTemplatePair deletes a template pair. The templates themselves are
+// left in place.
+func (s *ConsoleService) DeleteTemplatePair(ctx context.Context, pairID string) error {
+	path := fmt.Sprintf("/v1/console/card-template-pairs/%s", url.PathEscape(pairID))
+	err := s.client.Request(ctx, http.MethodDelete, path, nil, nil)
+	if err != nil {
+		return fmt.Errorf("error deleting template pair: %w", err)
+	}
+	return nil
+}
+
+// TemplatePairTemplates reads the Apple and Google templates of a pair
+func (s *ConsoleService) TemplatePairTemplates(ctx context.Context, pairID string) (apple, google *models.Template, err error) {
+	pair, err := s.ReadTemplatePair(ctx, pairID)
+	if err != nil {
+		return nil, nil, err
+	}
+	apple, err = s.ReadTemplate(ctx, pair.AppleCardTemplateID)
+	if err != nil {
+		return nil, nil, err
+	}
+	google, err = s.ReadTemplate(ctx, pair.GoogleCardTemplateID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return apple, google, nil
+}
+
+// ProvisionPair provisions linked Apple and Google cards from a template
+// pair in one call. Any CardTemplateID in params is ignored.
+func (s *AccessCardsService) ProvisionPair(ctx context.Context, pairID string, params models.ProvisionParams) (*models.UnifiedAccessPass, error) {
+	if pairID == "" {
+		return nil, errors.New("template pair ID is required")
+	}
+	params.CardTemplateID = ""
+	params.CardTemplatePairID = pairID
+
+	var raw json.RawMessage
+	err := s.client.Request(ctx, http.MethodPost, "/v1/key-cards", params, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("error provisioning card: %w", err)
+	}
+	u, err := models.ParseUnion(raw)
+	if err != nil {
+		return nil, err
+	}
+	uap, ok := models.AsUnifiedAccessPass(u)
+	if !ok {
+		return nil, fmt.Errorf("provisioning against template pair %s returned a single card %s", pairID, u.GetID())
+	}
+	return uap, nil
+}
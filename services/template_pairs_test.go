@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Access-Grid/accessgrid-go/client"
+	"github.com/Access-Grid/accessgrid-go/models"
+)
+
+func setupTemplatePairsTestServer(t *testing.T) (*client.Client, *map[string]interface{}) {
+	t.Helper()
+	provisioned := &map[string]interface{}{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v1/console/card-templates/0xapp1e":
+			json.NewEncoder(w).Encode(models.Template{ID: "0xapp1e", Platform: models.TemplatePlatformApple})
+		case r.URL.Path == "/v1/console/card-templates/0xg00g1e":
+			json.NewEncoder(w).Encode(models.Template{ID: "0xg00g1e", Platform: models.TemplatePlatformGoogle})
+		case r.URL.Path == "/v1/console/card-templates/0x4ndr01d":
+			json.NewEncoder(w).Encode(models.Template{ID: "0x4ndr01d", Platform: models.TemplatePlatformAndroid})
+		case r.URL.Path == "/v1/console/card-template-pairs" && r.Method == http.MethodPost:
+			var params models.CreateTemplatePairParams
+			json.NewDecoder(r.Body).Decode(&params)
+			json.NewEncoder(w).Encode(models.TemplatePair{
+				ID:                   "0xp41r",
+				Name:                 params.Name,
+				AppleCardTemplateID:  params.AppleCardTemplateID,
+				GoogleCardTemplateID: params.GoogleCardTemplateID,
+			})
+		case r.URL.Path == "/v1/console/card-template-pairs":
+			w.Write([]byte(`[{"id": "0xp41r", "name": "Employee badge", "apple_card_template_id": "0xapp1e", "google_card_template_id": "0xg00g1e"}]`))
+		case r.URL.Path == "/v1/console/card-template-pairs/0xp41r" && r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/v1/console/card-template-pairs/0xp41r":
+			w.Write([]byte(`{"id": "0xp41r", "name": "Employee badge", "apple_card_template_id": "0xapp1e", "google_card_template_id": "0xg00g1e"}`))
+		case r.URL.Path == "/v1/key-cards":
+			json.NewDecoder(r.Body).Decode(provisioned)
+			if (*provisioned)["card_template_pair_id"] == nil {
+				w.Write([]byte(`{"id": "0xc4rd", "state": "active"}`))
+				return
+			}
+			w.Write([]byte(`{"id": "uap_123", "install_url": "https://example.com/install", "details": [
+				{"id": "0xa", "platform": "apple", "state": "active"},
+				{"id": "0xg", "platform": "google", "state": "active"}
+			]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "not found"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	c, _ := client.NewClient("test-account", "test-secret", client.WithBaseURL(server.URL))
+	return c, provisioned
+}
+
+func TestConsoleService_TemplatePairs(t *testing.T) {
+	c, _ := setupTemplatePairsTestServer(t)
+	service := NewConsoleService(c)
+	ctx := context.Background()
+
+	pair, err := service.CreateTemplatePair(ctx, models.CreateTemplatePairParams{
+		Name:                 "Employee badge",
+		AppleCardTemplateID:  "0xapp1e",
+		GoogleCardTemplateID: "0xg00g1e",
+	})
+	if err != nil {
+		t.Fatalf("CreateTemplatePair() error = %v", err)
+	}
+	if pair.ID != "0xp41r" || pair.AppleCardTemplateID != "0xapp1e" {
+		t.Errorf("CreateTemplatePair() = %+v", pair)
+	}
+
+	// A Google template reported under the android alias is accepted
+	_, err = service.CreateTemplatePair(ctx, models.CreateTemplatePairParams{
+		AppleCardTemplateID:  "0xapp1e",
+		GoogleCardTemplateID: "0x4ndr01d",
+	})
+	if err != nil {
+		t.Errorf("CreateTemplatePair() with an android template error = %v", err)
+	}
+
+	// Templates on the wrong platforms are rejected before the API call
+	_, err = service.CreateTemplatePair(ctx, models.CreateTemplatePairParams{
+		AppleCardTemplateID:  "0xg00g1e",
+		GoogleCardTemplateID: "0xapp1e",
+	})
+	if err == nil || !strings.Contains(err.Error(), "platform") {
+		t.Errorf("CreateTemplatePair() with swapped templates error = %v", err)
+	}
+
+	pairs, err := service.ListTemplatePairs(ctx)
+	if err != nil || len(pairs) != 1 || pairs[0].GoogleCardTemplateID != "0xg00g1e" {
+		t.Errorf("ListTemplatePairs() = %+v, %v", pairs, err)
+	}
+
+	apple, google, err := service.TemplatePairTemplates(ctx, "0xp41r")
+	if err != nil {
+		t.Fatalf("TemplatePairTemplates() error = %v", err)
+	}
+	if apple.Platform != models.TemplatePlatformApple || google.Platform != models.TemplatePlatformGoogle {
+		t.Errorf("TemplatePairTemplates() = %+v, %+v", apple, google)
+	}
+
+	if err := service.DeleteTemplatePair(ctx, "0xp41r"); err != nil {
+		t.Errorf("DeleteTemplatePair() error = %v", err)
+	}
+}
+
+func TestAccessCardsService_ProvisionPair(t *testing.T) {
+	c, provisioned := setupTemplatePairsTestServer(t)
+	service := NewAccessCardsService(c)
+	ctx := context.Background()
+
+	pass, err := service.ProvisionPair(ctx, "0xp41r", models.ProvisionParams{
+		CardTemplateID: "0xignored",
+		EmployeeID:     "123456789",
+		FullName:       "Employee name",
+	})
+	if err != nil {
+		t.Fatalf("ProvisionPair() error = %v", err)
+	}
+	if (*provisioned)["card_template_pair_id"] != "0xp41r" || (*provisioned)["card_template_id"] != nil {
+		t.Errorf("provision request = %v", *provisioned)
+	}
+	if _, ok := pass.AppleCard(); !ok {
+		t.Error("ProvisionPair() pass has no Apple card")
+	}
+	if _, ok := pass.GoogleCard(); !ok {
+		t.Error("ProvisionPair() pass has no Google card")
+	}
+	if pass.State != models.CardStateActive {
+		t.Errorf("ProvisionPair() state = %q, want derived active", pass.State)
+	}
+
+	_, err = service.Provision(ctx, models.ProvisionParams{CardTemplateID: "0xd3adb00b5", CardTemplatePairID: "0xp41r"})
+	if err == nil {
+		t.Error("Provision() with both a template and a pair expected error")
+	}
+	_, err = service.Provision(ctx, models.ProvisionParams{FullName: "Employee name"})
+	if err == nil {
+		t.Error("Provision() without a template or a pair expected error")
+	}
+}
@@ -0,0 +1,296 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Access-Grid/accessgrid-go/internal/atomicfile"
+	"github.com/Access-Grid/accessgrid-go/models"
+)
+
+// CurrentTemplateVersion refers to the live template rather than a stored
+// snapshot in DiffTemplateVersions
+const CurrentTemplateVersion = 0
+
+// TemplateVersionStore persists template snapshots taken before updates
+type TemplateVersionStore interface {
+	// Save records a snapshot of template and returns it with the next
+	// version number for that template, starting at 1
+	Save(ctx context.Context, template models.Template) (*models.TemplateVersion, error)
+	// List returns a template's versions, oldest first
+	List(ctx context.Context, templateID string) ([]models.TemplateVersion, error)
+}
+
+// nextVersion appends a snapshot of template to versions
+func nextVersion(versions map[string][]models.TemplateVersion, template models.Template) models.TemplateVersion {
+	history := versions[template.ID]
+	version := models.TemplateVersion{
+		TemplateID: template.ID,
+		Version:    len(history) + 1,
+		SavedAt:    time.Now().UTC(),
+		Template:   template,
+	}
+	versions[template.ID] = append(history, version)
+	return version
+}
+
+// MemoryTemplateVersionStore is a TemplateVersionStore held in memory
+type MemoryTemplateVersionStore struct {
+	mu       sync.Mutex
+	versions map[string][]models.TemplateVersion
+}
+
+// NewMemoryTemplateVersionStore creates an empty MemoryTemplateVersionStore
+func NewMemoryTemplateVersionStore() *MemoryTemplateVersionStore {
+	return &MemoryTemplateVersionStore{versions: make(map[string][]models.TemplateVersion)}
+}
+
+// Save implements TemplateVersionStore
+func (s *MemoryTemplateVersionStore) Save(ctx context.Context, template models.Template) (*models.TemplateVersion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	version := nextVersion(s.versions, template)
+	return &version, nil
+}
+
+// List implements TemplateVersionStore
+func (s *MemoryTemplateVersionStore) List(ctx context.Context, templateID string) ([]models.TemplateVersion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]models.TemplateVersion(nil), s.versions[templateID]...), nil
+}
+
+// FileTemplateVersionStore is a TemplateVersionStore persisted as a JSON
+// file
+type FileTemplateVersionStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileTemplateVersionStore creates a FileTemplateVersionStore backed by
+// the file at path. The file is created on the first Save.
+func NewFileTemplateVersionStore(path string) *FileTemplateVersionStore {
+	return &FileTemplateVersionStore{path: path}
+}
+
+// Save implements TemplateVersionStore
+func (s *FileTemplateVersionStore) Save(ctx context.Context, template models.Template) (*models.TemplateVersion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	versions, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	version := nextVersion(versions, template)
+
+	data, err := json.Marshal(versions)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding template versions: %w", err)
+	}
+	if err := atomicfile.WriteFile(s.path, data, 0o644); err != nil {
+		return nil, fmt.Errorf("error writing template versions: %w", err)
+	}
+	return &version, nil
+}
+
+// List implements TemplateVersionStore
+func (s *FileTemplateVersionStore) List(ctx context.Context, templateID string) ([]models.TemplateVersion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	versions, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	return versions[templateID], nil
+}
+
+func (s *FileTemplateVersionStore) read() (map[string][]models.TemplateVersion, error) {
+	versions := make(map[string][]models.TemplateVersion)
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return versions, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading template versions: %w", err)
+	}
+	if err := json.Unmarshal(data, &versions); err != nil {
+		return nil, fmt.Errorf("error parsing template versions: %w", err)
+	}
+	return versions, nil
+}
+
+// templateVersioning holds the version store configured on a ConsoleService
+type templateVersioning struct {
+	mu      sync.Mutex
+	store   TemplateVersionStore
+	onError func(error)
+}
+
+func (v *templateVersioning) get() (TemplateVersionStore, func(error)) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return v.store, v.onError
+}
+
+func (v *templateVersioning) set(store TemplateVersionStore) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.store = store
+}
+
+func (v *templateVersioning) setOnError(fn func(error)) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.onError = fn
+}
+
+// SetTemplateVersionStore enables template version history. While a store
+// is set, UpdateTemplate reads the template before applying the update and
+// saves it as a new version once the update succeeds. A nil store disables
+// history.
+func (s *ConsoleService) SetTemplateVersionStore(store TemplateVersionStore) {
+	s.versions.set(store)
+}
+
+// OnTemplateSnapshotError sets a function called when UpdateTemplate cannot
+// read or save the snapshot of a template. Snapshots are best effort, so
+// such errors never fail the update itself; without a handler they are
+// dropped.
+func (s *ConsoleService) OnTemplateSnapshotError(fn func(error)) {
+	s.versions.setOnError(fn)
+}
+
+// templateSnapshot is the state of a template read before an update
+type templateSnapshot struct {
+	store    TemplateVersionStore
+	onError  func(error)
+	template *models.Template
+}
+
+// readSnapshot reads a template ahead of an update if a version store is
+// configured. It returns nil if there is no store or the read fails.
+func (s *ConsoleService) readSnapshot(ctx context.Context, templateID string) *templateSnapshot {
+	store, onError := s.versions.get()
+	if store == nil {
+		return nil
+	}
+
+	current, err := s.ReadTemplate(ctx, templateID)
+	if err != nil {
+		if onError != nil {
+			onError(fmt.Errorf("error snapshotting template %s: %w", templateID, err))
+		}
+		return nil
+	}
+	return &templateSnapshot{store: store, onError: onError, template: current}
+}
+
+// save stores the snapshot as a new version. It is called only after the
+// update succeeded, so failed updates leave no version behind.
+func (snap *templateSnapshot) save(ctx context.Context) {
+	if snap == nil {
+		return
+	}
+	if _, err := snap.store.Save(ctx, *snap.template); err != nil && snap.onError != nil {
+		snap.onError(fmt.Errorf("error snapshotting template %s: %w", snap.template.ID, err))
+	}
+}
+
+func (s *ConsoleService) versionStore() (TemplateVersionStore, error) {
+	store, _ := s.versions.get()
+	if store == nil {
+		return nil, errors.New("no template version store configured")
+	}
+	return store, nil
+}
+
+// TemplateVersions lists the stored versions of a template, oldest first
+func (s *ConsoleService) TemplateVersions(ctx context.Context, templateID string) ([]models.TemplateVersion, error) {
+	store, err := s.versionStore()
+	if err != nil {
+		return nil, err
+	}
+	versions, err := store.List(ctx, templateID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing template versions: %w", err)
+	}
+	return versions, nil
+}
+
+// TemplateVersion returns one stored version of a template
+func (s *ConsoleService) TemplateVersion(ctx context.Context, templateID string, version int) (*models.TemplateVersion, error) {
+	versions, err := s.TemplateVersions(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range versions {
+		if v.Version == version {
+			return &v, nil
+		}
+	}
+	return nil, fmt.Errorf("template %s has no version %d", templateID, version)
+}
+
+// DiffTemplateVersions lists the fields that changed between two versions
+// of a template. Either version may be CurrentTemplateVersion to compare
+// against the live template.
+func (s *ConsoleService) DiffTemplateVersions(ctx context.Context, templateID string, from, to int) ([]models.TemplateChange, error) {
+	resolve := func(version int) (*models.Template, error) {
+		if version == CurrentTemplateVersion {
+			return s.ReadTemplate(ctx, templateID)
+		}
+		v, err := s.TemplateVersion(ctx, templateID, version)
+		if err != nil {
+			return nil, err
+		}
+		return &v.Template, nil
+	}
+
+	a, err := resolve(from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := resolve(to)
+	if err != nil {
+		return nil, err
+	}
+	return models.DiffTemplates(*a, *b), nil
+}
+
+// RollbackTemplate restores the name, device counts, design and support
+// info of a stored version by issuing an UpdateTemplate. The update is
+// itself snapshotted, so a rollback can be undone. Because the API ignores
+// empty fields in an update, values that were empty or zero in the stored
+// version are left as they are on the live template.
+func (s *ConsoleService) RollbackTemplate(ctx context.Context, templateID string, version int) (*models.Template, error) {
+	v, err := s.TemplateVersion(ctx, templateID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	old := v.Template
+	template, err := s.UpdateTemplate(ctx, models.UpdateTemplateParams{
+		CardTemplateID: templateID,
+		Name:           old.Name,
+		WatchCount:     old.WatchCount,
+		IPhoneCount:    old.IPhoneCount,
+		Design:         &old.Design,
+		SupportInfo:    &old.SupportInfo,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error rolling back template %s to version %d: %w", templateID, version, err)
+	}
+	return template, nil
+}
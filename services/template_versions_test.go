@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/Access-Grid/accessgrid-go/client"
+	"github.com/Access-Grid/accessgrid-go/models"
+)
+
+// templateBackend serves a single template that PUT requests modify
+type templateBackend struct {
+	mu       sync.Mutex
+	template models.Template
+	updates  []models.UpdateTemplateParams
+	// failUpdates makes PUT requests fail
+	failUpdates bool
+}
+
+func (b *templateBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if r.URL.Path != "/v1/console/card-templates/"+b.template.ID {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message": "not found"}`))
+		return
+	}
+
+	if r.Method == http.MethodPut && b.failUpdates {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"message": "invalid design"}`))
+		return
+	}
+	if r.Method == http.MethodPut {
+		var params models.UpdateTemplateParams
+		json.NewDecoder(r.Body).Decode(&params)
+		b.updates = append(b.updates, params)
+		if params.Name != "" {
+			b.template.Name = params.Name
+		}
+		if params.WatchCount != 0 {
+			b.template.WatchCount = params.WatchCount
+		}
+		if params.Design != nil {
+			b.template.Design = *params.Design
+		}
+		if params.SupportInfo != nil {
+			b.template.SupportInfo = *params.SupportInfo
+		}
+	}
+	json.NewEncoder(w).Encode(b.template)
+}
+
+func setupTemplateVersionsTestServer(t *testing.T) (*templateBackend, *ConsoleService) {
+	t.Helper()
+	backend := &templateBackend{template: models.Template{
+		ID:         "0xd3adb00b5",
+		Name:       "Employee NFC key",
+		Platform:   models.TemplatePlatformApple,
+		WatchCount: 2,
+		Design:     models.TemplateDesign{BackgroundColor: "#FFFFFF", LabelColor: "#000000"},
+	}}
+	server := httptest.NewServer(backend)
+	t.Cleanup(server.Close)
+
+	c, _ := client.NewClient("test-account", "test-secret", client.WithBaseURL(server.URL))
+	return backend, NewConsoleService(c)
+}
+
+func TestConsoleService_TemplateVersions(t *testing.T) {
+	backend, service := setupTemplateVersionsTestServer(t)
+	service.SetTemplateVersionStore(NewMemoryTemplateVersionStore())
+	ctx := context.Background()
+
+	for _, color := range []string{"#FF0000", "#00FF00"} {
+		_, err := service.UpdateTemplate(ctx, models.UpdateTemplateParams{
+			CardTemplateID: "0xd3adb00b5",
+			Design:         &models.TemplateDesign{BackgroundColor: color, LabelColor: "#000000"},
+		})
+		if err != nil {
+			t.Fatalf("UpdateTemplate() error = %v", err)
+		}
+	}
+
+	versions, err := service.TemplateVersions(ctx, "0xd3adb00b5")
+	if err != nil {
+		t.Fatalf("TemplateVersions() error = %v", err)
+	}
+	if len(versions) != 2 || versions[0].Version != 1 || versions[0].Template.Design.BackgroundColor != "#FFFFFF" || versions[1].Template.Design.BackgroundColor != "#FF0000" {
+		t.Fatalf("TemplateVersions() = %+v", versions)
+	}
+
+	changes, err := service.DiffTemplateVersions(ctx, "0xd3adb00b5", 1, CurrentTemplateVersion)
+	if err != nil {
+		t.Fatalf("DiffTemplateVersions() error = %v", err)
+	}
+	if len(changes) != 1 || changes[0] != (models.TemplateChange{Field: "design.background_color", From: "#FFFFFF", To: "#00FF00"}) {
+		t.Errorf("DiffTemplateVersions() = %+v", changes)
+	}
+
+	template, err := service.RollbackTemplate(ctx, "0xd3adb00b5", 1)
+	if err != nil {
+		t.Fatalf("RollbackTemplate() error = %v", err)
+	}
+	if template.Design.BackgroundColor != "#FFFFFF" || backend.template.Design.BackgroundColor != "#FFFFFF" {
+		t.Errorf("RollbackTemplate() design = %+v, want original", template.Design)
+	}
+
+	// The rollback is snapshotted like any other update
+	versions, _ = service.TemplateVersions(ctx, "0xd3adb00b5")
+	if len(versions) != 3 || versions[2].Template.Design.BackgroundColor != "#00FF00" {
+		t.Errorf("TemplateVersions() after rollback = %+v", versions)
+	}
+
+	if _, err := service.RollbackTemplate(ctx, "0xd3adb00b5", 9); err == nil {
+		t.Error("RollbackTemplate() with unknown version expected error")
+	}
+}
+
+func TestConsoleService_TemplateVersionsDisabled(t *testing.T) {
+	backend, service := setupTemplateVersionsTestServer(t)
+	ctx := context.Background()
+
+	if _, err := service.UpdateTemplate(ctx, models.UpdateTemplateParams{CardTemplateID: "0xd3adb00b5", Name: "Renamed"}); err != nil {
+		t.Fatalf("UpdateTemplate() error = %v", err)
+	}
+	if len(backend.updates) != 1 {
+		t.Errorf("updates = %d, want 1", len(backend.updates))
+	}
+	if _, err := service.TemplateVersions(ctx, "0xd3adb00b5"); err == nil {
+		t.Error("TemplateVersions() without a store expected error")
+	}
+}
+
+func TestConsoleService_TemplateSnapshotsAreBestEffort(t *testing.T) {
+	backend, service := setupTemplateVersionsTestServer(t)
+	store := NewMemoryTemplateVersionStore()
+	service.SetTemplateVersionStore(store)
+	ctx := context.Background()
+
+	// A failed update leaves no version behind
+	backend.failUpdates = true
+	if _, err := service.UpdateTemplate(ctx, models.UpdateTemplateParams{CardTemplateID: "0xd3adb00b5", Name: "Renamed"}); err == nil {
+		t.Fatal("UpdateTemplate() expected error")
+	}
+	if versions, _ := store.List(ctx, "0xd3adb00b5"); len(versions) != 0 {
+		t.Errorf("versions after failed update = %+v, want none", versions)
+	}
+
+	// A snapshot that cannot be saved doesn't fail the update
+	backend.failUpdates = false
+	var snapshotErrs []error
+	service.OnTemplateSnapshotError(func(err error) { snapshotErrs = append(snapshotErrs, err) })
+	service.SetTemplateVersionStore(NewFileTemplateVersionStore(filepath.Join(t.TempDir(), "missing", "versions.json")))
+	if _, err := service.UpdateTemplate(ctx, models.UpdateTemplateParams{CardTemplateID: "0xd3adb00b5", Name: "Renamed"}); err != nil {
+		t.Fatalf("UpdateTemplate() error = %v", err)
+	}
+	if backend.template.Name != "Renamed" || len(snapshotErrs) != 1 {
+		t.Errorf("name = %q, snapshot errors = %v, want update applied and one error", backend.template.Name, snapshotErrs)
+	}
+}
+
+func TestFileTemplateVersionStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "versions.json")
+	ctx := context.Background()
+
+	store := NewFileTemplateVersionStore(path)
+	for _, name := range []string{"First", "Second"} {
+		if _, err := store.Save(ctx, models.Template{ID: "0xd3adb00b5", Name: name}); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	// A new store reads what the first one wrote
+	versions, err := NewFileTemplateVersionStore(path).List(ctx, "0xd3adb00b5")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(versions) != 2 || versions[1].Version != 2 || versions[1].Template.Name != "Second" {
+		t.Errorf("List() = %+v", versions)
+	}
+
+	if versions, _ := store.List(ctx, "0xunknown"); len(versions) != 0 {
+		t.Errorf("List(unknown) = %+v, want none", versions)
+	}
+}
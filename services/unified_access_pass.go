@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/Access-Grid/accessgrid-go/models"
+)
+
+// cardOperation is a lifecycle operation applied to a single card
+type cardOperation func(ctx context.Context, cardID string) error
+
+// SuspendAll suspends every card in an access pass. For a UnifiedAccessPass
+// each child card is suspended concurrently.
+func (s *AccessCardsService) SuspendAll(ctx context.Context, pass models.Union) error {
+	return s.fanOut(ctx, pass, s.Suspend)
+}
+
+// ResumeAll resumes every card in an access pass. For a UnifiedAccessPass
+// each child card is resumed concurrently.
+func (s *AccessCardsService) ResumeAll(ctx context.Context, pass models.Union) error {
+	return s.fanOut(ctx, pass, s.Resume)
+}
+
+// UnlinkAll unlinks every card in an access pass. For a UnifiedAccessPass
+// each child card is unlinked concurrently.
+func (s *AccessCardsService) UnlinkAll(ctx context.Context, pass models.Union) error {
+	return s.fanOut(ctx, pass, s.Unlink)
+}
+
+// DeleteAll deletes every card in an access pass. For a UnifiedAccessPass
+// each child card is deleted concurrently.
+func (s *AccessCardsService) DeleteAll(ctx context.Context, pass models.Union) error {
+	return s.fanOut(ctx, pass, s.Delete)
+}
+
+// fanOut applies op to every card in pass concurrently. Failures do not stop
+// the remaining operations; they are joined into a single error naming each
+// failed card.
+func (s *AccessCardsService) fanOut(ctx context.Context, pass models.Union, op cardOperation) error {
+	if pass == nil {
+		return errors.New("access pass is required")
+	}
+
+	cards := pass.GetCards()
+	errs := make([]error, len(cards))
+
+	var wg sync.WaitGroup
+	for i, card := range cards {
+		wg.Add(1)
+		go func(i int, cardID string) {
+			defer wg.Done()
+			if err := op(ctx, cardID); err != nil {
+				errs[i] = fmt.Errorf("card %s: %w", cardID, err)
+			}
+		}(i, card.ID)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Access-Grid/accessgrid-go/client"
+	"github.com/Access-Grid/accessgrid-go/models"
+)
+
+func setupUnifiedAccessPassTestServer() (*httptest.Server, *AccessCardsService, *sync.Map) {
+	calls := &sync.Map{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		calls.Store(r.URL.Path, true)
+
+		switch r.URL.Path {
+		case "/v1/key-cards/0xp4ss":
+			// Get unified pass without a top-level state
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{
+				"id": "0xp4ss",
+				"install_url": "https://accessgrid.com/install/0xp4ss",
+				"details": [
+					{"id": "0xapple", "platform": "apple", "state": "active"},
+					{"id": "0xgoogle", "platform": "google", "state": "suspended"}
+				]
+			}`))
+		case "/v1/key-cards/0xapple/suspend", "/v1/key-cards/0xgoogle/suspend":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{}`))
+		case "/v1/key-cards/0xapple/delete":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{}`))
+		case "/v1/key-cards/0xgoogle/delete":
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"message": "delete failed"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	c, _ := client.NewClient("test-account", "test-secret", client.WithBaseURL(server.URL))
+	service := NewAccessCardsService(c)
+
+	return server, service, calls
+}
+
+func TestAccessCardsService_GetUnifiedAccessPass(t *testing.T) {
+	server, service, _ := setupUnifiedAccessPassTestServer()
+	defer server.Close()
+
+	result, err := service.Get(context.Background(), "0xp4ss")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	uap, ok := result.(*models.UnifiedAccessPass)
+	if !ok {
+		t.Fatalf("Get() expected *models.UnifiedAccessPass, got %T", result)
+	}
+	if uap.GetState() != models.StateMixed {
+		t.Errorf("Get() uap.GetState() = %v, want %v", uap.GetState(), models.StateMixed)
+	}
+	if got := uap.ChildStates()["0xgoogle"]; got != "suspended" {
+		t.Errorf("Get() uap.ChildStates()[0xgoogle] = %v, want %v", got, "suspended")
+	}
+
+	google, ok := uap.GoogleCard()
+	if !ok || google.ID != "0xgoogle" {
+		t.Errorf("GoogleCard() = %v, %v, want 0xgoogle", google, ok)
+	}
+	if _, ok := uap.CardForPlatform(models.DevicePlatformAndroid); ok {
+		t.Error("CardForPlatform(android) expected no card")
+	}
+}
+
+func TestAccessCardsService_SuspendAll(t *testing.T) {
+	server, service, calls := setupUnifiedAccessPassTestServer()
+	defer server.Close()
+
+	pass := &models.UnifiedAccessPass{
+		ID:      "0xp4ss",
+		Details: []models.Card{{ID: "0xapple"}, {ID: "0xgoogle"}},
+	}
+
+	if err := service.SuspendAll(context.Background(), pass); err != nil {
+		t.Fatalf("SuspendAll() error = %v", err)
+	}
+
+	for _, path := range []string{"/v1/key-cards/0xapple/suspend", "/v1/key-cards/0xgoogle/suspend"} {
+		if _, ok := calls.Load(path); !ok {
+			t.Errorf("SuspendAll() expected request to %s", path)
+		}
+	}
+}
+
+func TestAccessCardsService_DeleteAllAggregatesErrors(t *testing.T) {
+	server, service, calls := setupUnifiedAccessPassTestServer()
+	defer server.Close()
+
+	pass := &models.UnifiedAccessPass{
+		ID:      "0xp4ss",
+		Details: []models.Card{{ID: "0xapple"}, {ID: "0xgoogle"}},
+	}
+
+	err := service.DeleteAll(context.Background(), pass)
+	if err == nil {
+		t.Fatal("DeleteAll() expected error")
+	}
+	if !strings.Contains(err.Error(), "card 0xgoogle") {
+		t.Errorf("DeleteAll() error = %v, want mention of 0xgoogle", err)
+	}
+	if strings.Contains(err.Error(), "card 0xapple") {
+		t.Errorf("DeleteAll() error = %v, want no mention of 0xapple", err)
+	}
+	if _, ok := calls.Load("/v1/key-cards/0xapple/delete"); !ok {
+		t.Error("DeleteAll() expected 0xapple to be deleted despite sibling failure")
+	}
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/Access-Grid/accessgrid-go/client"
+	"github.com/Access-Grid/accessgrid-go/models"
+)
+
+// WebhooksService handles operations related to webhook endpoints
+type WebhooksService struct {
+	client *client.Client
+}
+
+// NewWebhooksService creates a new WebhooksService
+func NewWebhooksService(client *client.Client) *WebhooksService {
+	return &WebhooksService{client: client}
+}
+
+// Create registers a new webhook endpoint. The returned webhook includes the
+// endpoint's signing secret, which is not returned again.
+func (s *WebhooksService) Create(ctx context.Context, params models.CreateWebhookParams) (*models.Webhook, error) {
+	if params.URL == "" {
+		return nil, errors.New("webhook URL is required")
+	}
+	if len(params.EventTypes) == 0 {
+		return nil, errors.New("at least one event type is required")
+	}
+
+	var webhook models.Webhook
+	err := s.client.Request(ctx, http.MethodPost, "/v1/webhooks", params, &webhook)
+	if err != nil {
+		return nil, fmt.Errorf("error creating webhook: %w", err)
+	}
+	return &webhook, nil
+}
+
+// Get retrieves a webhook endpoint by ID
+func (s *WebhooksService) Get(ctx context.Context, webhookID string) (*models.Webhook, error) {
+	var webhook models.Webhook
+	path := fmt.Sprintf("/v1/webhooks/%s", url.PathEscape(webhookID))
+	err := s.client.Request(ctx, http.MethodGet, path, nil, &webhook)
+	if err != nil {
+		return nil, fmt.Errorf("error getting webhook: %w", err)
+	}
+	return &webhook, nil
+}
+
+// List retrieves all webhook endpoints
+func (s *WebhooksService) List(ctx context.Context) ([]models.Webhook, error) {
+	var response struct {
+		Webhooks []models.Webhook `json:"webhooks"`
+	}
+	err := s.client.Request(ctx, http.MethodGet, "/v1/webhooks", nil, &response)
+	if err != nil {
+		return nil, fmt.Errorf("error listing webhooks: %w", err)
+	}
+	return response.Webhooks, nil
+}
+
+// Update updates a webhook endpoint's URL, description, subscribed event
+// types or enabled state
+func (s *WebhooksService) Update(ctx context.Context, params models.UpdateWebhookParams) (*models.Webhook, error) {
+	var webhook models.Webhook
+	path := fmt.Sprintf("/v1/webhooks/%s", url.PathEscape(params.WebhookID))
+	err := s.client.Request(ctx, http.MethodPatch, path, params, &webhook)
+	if err != nil {
+		return nil, fmt.Errorf("error updating webhook: %w", err)
+	}
+	return &webhook, nil
+}
+
+// Delete deletes a webhook endpoint
+func (s *WebhooksService) Delete(ctx context.Context, webhookID string) error {
+	path := fmt.Sprintf("/v1/webhooks/%s", url.PathEscape(webhookID))
+	err := s.client.Request(ctx, http.MethodDelete, path, nil, nil)
+	if err != nil {
+		return fmt.Errorf("error deleting webhook: %w", err)
+	}
+	return nil
+}
+
+// Test sends a signed test event to a webhook endpoint and reports whether
+// the endpoint accepted it
+func (s *WebhooksService) Test(ctx context.Context, webhookID string) (*models.WebhookTestResult, error) {
+	var result models.WebhookTestResult
+	path := fmt.Sprintf("/v1/webhooks/%s/test", url.PathEscape(webhookID))
+	err := s.client.Request(ctx, http.MethodPost, path, map[string]string{}, &result)
+	if err != nil {
+		return nil, fmt.Errorf("error testing webhook: %w", err)
+	}
+	return &result, nil
+}
+
+// RotateSecret replaces a webhook endpoint's signing secret. The returned
+// webhook includes the new secret.
+func (s *WebhooksService) RotateSecret(ctx context.Context, webhookID string) (*models.Webhook, error) {
+	var webhook models.Webhook
+	path := fmt.Sprintf("/v1/webhooks/%s/rotate-secret", url.PathEscape(webhookID))
+	err := s.client.Request(ctx, http.MethodPost, path, map[string]string{}, &webhook)
+	if err != nil {
+		return nil, fmt.Errorf("error rotating webhook secret: %w", err)
+	}
+	return &webhook, nil
+}
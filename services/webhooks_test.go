@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Access-Grid/accessgrid-go/accessgridtest"
+	"github.com/Access-Grid/accessgrid-go/client"
+	"github.com/Access-Grid/accessgrid-go/models"
+	"github.com/Access-Grid/accessgrid-go/webhook"
+)
+
+func setupWebhooksTest(t *testing.T) (*accessgridtest.Server, *WebhooksService) {
+	t.Helper()
+	server := accessgridtest.NewServer()
+	c, _ := client.NewClient("test-account", "test-secret", client.WithBaseURL(server.URL))
+	return server, NewWebhooksService(c)
+}
+
+// receiver is a webhook endpoint whose signing secret can be swapped
+type receiver struct {
+	*httptest.Server
+	handler atomic.Pointer[webhook.Handler]
+}
+
+func newReceiver(t *testing.T) *receiver {
+	t.Helper()
+	r := &receiver{}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.handler.Load().ServeHTTP(w, req)
+	}))
+	return r
+}
+
+func (r *receiver) useSecret(t *testing.T, secret string) {
+	t.Helper()
+	handler, err := webhook.NewHandler(secret)
+	if err != nil {
+		t.Fatalf("webhook.NewHandler() error = %v", err)
+	}
+	r.handler.Store(handler)
+}
+
+func TestWebhooksService_CRUD(t *testing.T) {
+	server, service := setupWebhooksTest(t)
+	defer server.Close()
+
+	ctx := context.Background()
+	created, err := service.Create(ctx, models.CreateWebhookParams{
+		URL:        "https://example.com/webhooks",
+		EventTypes: []models.WebhookEventType{models.WebhookEventCardSuspended},
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if created.Secret == "" {
+		t.Error("Create() expected signing secret")
+	}
+
+	enabled := false
+	updated, err := service.Update(ctx, models.UpdateWebhookParams{
+		WebhookID:  created.ID,
+		EventTypes: []models.WebhookEventType{models.WebhookEventCardSuspended, models.WebhookEventCardResumed},
+		Enabled:    &enabled,
+	})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if updated.Enabled || len(updated.EventTypes) != 2 {
+		t.Errorf("Update() = %+v, want disabled with 2 event types", updated)
+	}
+	if updated.Secret != "" {
+		t.Error("Update() unexpectedly returned the signing secret")
+	}
+
+	webhooks, err := service.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(webhooks) != 1 || webhooks[0].ID != created.ID {
+		t.Errorf("List() = %+v, want [%s]", webhooks, created.ID)
+	}
+
+	if err := service.Delete(ctx, created.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := service.Get(ctx, created.ID); !client.IsNotFound(err) {
+		t.Errorf("Get() after Delete error = %v, want not found", err)
+	}
+}
+
+func TestWebhooksService_TestAndRotateSecret(t *testing.T) {
+	server, service := setupWebhooksTest(t)
+	defer server.Close()
+
+	endpoint := newReceiver(t)
+	defer endpoint.Close()
+
+	ctx := context.Background()
+	created, err := service.Create(ctx, models.CreateWebhookParams{
+		URL:        endpoint.URL,
+		EventTypes: []models.WebhookEventType{models.WebhookEventCardCreated},
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	endpoint.useSecret(t, created.Secret)
+
+	result, err := service.Test(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("Test() error = %v", err)
+	}
+	if !result.Delivered {
+		t.Errorf("Test() = %+v, want delivered", result)
+	}
+
+	rotated, err := service.RotateSecret(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("RotateSecret() error = %v", err)
+	}
+	if rotated.Secret == "" || rotated.Secret == created.Secret {
+		t.Fatalf("RotateSecret() secret = %q, want a new secret", rotated.Secret)
+	}
+
+	// The endpoint still verifies with the old secret, so delivery fails
+	result, _ = service.Test(ctx, created.ID)
+	if result.Delivered || result.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Test() with stale secret = %+v, want 401", result)
+	}
+
+	endpoint.useSecret(t, rotated.Secret)
+	result, _ = service.Test(ctx, created.ID)
+	if !result.Delivered {
+		t.Errorf("Test() with rotated secret = %+v, want delivered", result)
+	}
+}
@@ -0,0 +1,298 @@
+package templateconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Access-Grid/accessgrid-go/client"
+	"github.com/Access-Grid/accessgrid-go/models"
+	"github.com/Access-Grid/accessgrid-go/services"
+)
+
+// Actions reported for each template
+const (
+	// ActionCreate means the template does not exist yet
+	ActionCreate = "create"
+	// ActionUpdate means mutable fields have drifted from the config
+	ActionUpdate = "update"
+	// ActionUnchanged means the template matches the config
+	ActionUnchanged = "unchanged"
+	// ActionRecreateRequired means a field the API cannot update, such as
+	// the platform or protocol, has drifted. Mutable fields are still
+	// updated; the template must be recreated by hand to fix the rest.
+	ActionRecreateRequired = "recreate_required"
+	// ActionOrphaned means the state references a key no longer in the
+	// config. Orphaned templates are never deleted automatically.
+	ActionOrphaned = "orphaned"
+)
+
+// FieldDrift is a field whose actual value differs from the config
+type FieldDrift struct {
+	Field     string `json:"field"`
+	Want      string `json:"want"`
+	Have      string `json:"have"`
+	Immutable bool   `json:"immutable,omitempty"`
+}
+
+// Result describes what was, or would be, done to a single template
+type Result struct {
+	Key        string       `json:"key"`
+	TemplateID string       `json:"template_id,omitempty"`
+	Action     string       `json:"action"`
+	Drift      []FieldDrift `json:"drift,omitempty"`
+	Error      string       `json:"error,omitempty"`
+}
+
+// Report lists the result for every template in the config and state
+type Report struct {
+	DryRun  bool     `json:"dry_run"`
+	Results []Result `json:"results"`
+}
+
+// HasDrift reports whether any template differs from the config
+func (r *Report) HasDrift() bool {
+	for _, result := range r.Results {
+		if result.Action != ActionUnchanged {
+			return true
+		}
+	}
+	return false
+}
+
+// Write prints the report in a human-readable form
+func (r *Report) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, result := range r.Results {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", result.Action, result.Key, result.TemplateID, result.Error)
+		for _, d := range result.Drift {
+			note := ""
+			if d.Immutable {
+				note = " (cannot be updated)"
+			}
+			fmt.Fprintf(tw, "\t%s\t%q -> %q%s\t\n", d.Field, d.Have, d.Want, note)
+		}
+	}
+	return tw.Flush()
+}
+
+// Applier compares and applies template configs through ConsoleService
+type Applier struct {
+	console *services.ConsoleService
+	now     func() time.Time
+}
+
+// NewApplier creates a new Applier
+func NewApplier(console *services.ConsoleService) (*Applier, error) {
+	if console == nil {
+		return nil, errors.New("console service is required")
+	}
+	return &Applier{console: console, now: time.Now}, nil
+}
+
+// Plan reports how each template differs from the config without changing
+// anything
+func (a *Applier) Plan(ctx context.Context, cfg *Config, state *State) (*Report, error) {
+	return a.run(ctx, cfg, state, false)
+}
+
+// Apply creates missing templates and updates drifted ones, recording new
+// template IDs in state. The caller is responsible for saving state, which
+// should be done even when Apply returns an error so that templates created
+// before the failure are not created again.
+func (a *Applier) Apply(ctx context.Context, cfg *Config, state *State) (*Report, error) {
+	return a.run(ctx, cfg, state, true)
+}
+
+func (a *Applier) run(ctx context.Context, cfg *Config, state *State, apply bool) (*Report, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if state.Templates == nil {
+		state.Templates = make(map[string]StateEntry)
+	}
+
+	report := &Report{DryRun: !apply}
+	var errs []error
+	configured := make(map[string]bool, len(cfg.Templates))
+
+	for _, spec := range cfg.Templates {
+		configured[spec.Key] = true
+		result, err := a.reconcile(ctx, spec, state, apply)
+		if err != nil {
+			result.Error = err.Error()
+			errs = append(errs, fmt.Errorf("template %q: %w", spec.Key, err))
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	for _, key := range state.Keys() {
+		if !configured[key] {
+			report.Results = append(report.Results, Result{
+				Key:        key,
+				TemplateID: state.Templates[key].ID,
+				Action:     ActionOrphaned,
+			})
+		}
+	}
+
+	return report, errors.Join(errs...)
+}
+
+func (a *Applier) reconcile(ctx context.Context, spec TemplateSpec, state *State, apply bool) (Result, error) {
+	result := Result{Key: spec.Key}
+
+	var current *models.Template
+	if entry, ok := state.Templates[spec.Key]; ok {
+		result.TemplateID = entry.ID
+		template, err := a.console.ReadTemplate(ctx, entry.ID)
+		switch {
+		case client.IsNotFound(err):
+			// Deleted outside of the config; create it again
+			result.TemplateID = ""
+		case err != nil:
+			return result, err
+		default:
+			current = template
+		}
+	}
+
+	if current == nil {
+		result.Action = ActionCreate
+		if !apply {
+			return result, nil
+		}
+		created, err := a.console.CreateTemplate(ctx, spec.CreateTemplateParams)
+		if err != nil {
+			return result, err
+		}
+		result.TemplateID = created.ID
+		state.Templates[spec.Key] = StateEntry{ID: created.ID, AppliedAt: a.now()}
+		return result, nil
+	}
+
+	result.Drift = diffTemplate(spec, current)
+	if len(result.Drift) == 0 {
+		result.Action = ActionUnchanged
+		return result, nil
+	}
+
+	result.Action = ActionUpdate
+	for _, d := range result.Drift {
+		if d.Immutable {
+			result.Action = ActionRecreateRequired
+		}
+	}
+
+	params, ok := updateParams(spec, current)
+	if !apply || !ok {
+		return result, nil
+	}
+	if _, err := a.console.UpdateTemplate(ctx, params); err != nil {
+		return result, err
+	}
+	state.Templates[spec.Key] = StateEntry{ID: current.ID, AppliedAt: a.now()}
+	return result, nil
+}
+
+// diffTemplate lists the managed fields of spec that differ from template
+func diffTemplate(spec TemplateSpec, template *models.Template) []FieldDrift {
+	var drift []FieldDrift
+	compare := func(field, want, have string, immutable bool) {
+		if want != "" && want != have {
+			drift = append(drift, FieldDrift{Field: field, Want: want, Have: have, Immutable: immutable})
+		}
+	}
+	count := func(field string, want, have int) {
+		if want != 0 && want != have {
+			compare(field, strconv.Itoa(want), strconv.Itoa(have), false)
+		}
+	}
+
+	compare("name", spec.Name, template.Name, false)
+	compare("platform", string(spec.Platform), string(template.Platform), true)
+	compare("use_case", string(spec.UseCase), string(template.UseCase), true)
+	compare("protocol", string(spec.Protocol), string(template.Protocol), true)
+	count("watch_count", spec.WatchCount, template.WatchCount)
+	count("iphone_count", spec.IPhoneCount, template.IPhoneCount)
+
+	for _, f := range diffStrings(spec.Design, template.Design) {
+		compare("design."+f.Field, f.Want, f.Have, false)
+	}
+	for _, f := range diffStrings(spec.SupportInfo, template.SupportInfo) {
+		compare("support_info."+f.Field, f.Want, f.Have, false)
+	}
+
+	return drift
+}
+
+// updateParams builds the update for the mutable fields of spec that differ
+// from template. Unmanaged design and support fields keep their current
+// values.
+func updateParams(spec TemplateSpec, template *models.Template) (models.UpdateTemplateParams, bool) {
+	params := models.UpdateTemplateParams{CardTemplateID: template.ID}
+	changed := false
+
+	if spec.Name != "" && spec.Name != template.Name {
+		params.Name = spec.Name
+		changed = true
+	}
+	if spec.WatchCount != 0 && spec.WatchCount != template.WatchCount {
+		params.WatchCount = spec.WatchCount
+		changed = true
+	}
+	if spec.IPhoneCount != 0 && spec.IPhoneCount != template.IPhoneCount {
+		params.IPhoneCount = spec.IPhoneCount
+		changed = true
+	}
+	if len(diffStrings(spec.Design, template.Design)) > 0 {
+		design := template.Design
+		overlayStrings(&design, spec.Design)
+		params.Design = &design
+		changed = true
+	}
+	if len(diffStrings(spec.SupportInfo, template.SupportInfo)) > 0 {
+		support := template.SupportInfo
+		overlayStrings(&support, spec.SupportInfo)
+		params.SupportInfo = &support
+		changed = true
+	}
+
+	return params, changed
+}
+
+// diffStrings compares the string fields of two structs of the same type,
+// ignoring fields that are empty in want
+func diffStrings(want, have interface{}) []FieldDrift {
+	var drift []FieldDrift
+	wv, hv := reflect.ValueOf(want), reflect.ValueOf(have)
+	for i := 0; i < wv.NumField(); i++ {
+		field := wv.Type().Field(i)
+		if field.Type.Kind() != reflect.String {
+			continue
+		}
+		w, h := wv.Field(i).String(), hv.Field(i).String()
+		if w != "" && w != h {
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			drift = append(drift, FieldDrift{Field: name, Want: w, Have: h})
+		}
+	}
+	return drift
+}
+
+// overlayStrings copies the non-empty string fields of src onto dst, which
+// must be a pointer to a struct of the same type
+func overlayStrings(dst, src interface{}) {
+	dv, sv := reflect.ValueOf(dst).Elem(), reflect.ValueOf(src)
+	for i := 0; i < sv.NumField(); i++ {
+		if sv.Field(i).Kind() == reflect.String && sv.Field(i).String() != "" {
+			dv.Field(i).SetString(sv.Field(i).String())
+		}
+	}
+}
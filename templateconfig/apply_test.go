@@ -0,0 +1,196 @@
+package templateconfig
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Access-Grid/accessgrid-go/client"
+	"github.com/Access-Grid/accessgrid-go/models"
+	"github.com/Access-Grid/accessgrid-go/services"
+)
+
+const testConfig = `{
+	"templates": [
+		{
+			"key": "employee-badge",
+			"name": "Employee NFC key",
+			"platform": "apple",
+			"protocol": "desfire",
+			"watch_count": 2,
+			"design": {"background_color": "#000000"}
+		},
+		{
+			"key": "parking",
+			"name": "Parking pass",
+			"platform": "google",
+			"use_case": "residential",
+			"protocol": "desfire",
+			"support_info": {
+				"support_url": "https://help.example.com",
+				"privacy_policy_url": "https://example.com/privacy",
+				"terms_and_conditions_url": "https://example.com/terms"
+			}
+		}
+	]
+}`
+
+func setupTemplateConfigTestServer() (*httptest.Server, *services.ConsoleService, *sync.Map) {
+	calls := &sync.Map{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.URL.Path == "/v1/console/card-templates/0xd3adb00b5" && r.Method == http.MethodGet:
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{
+				"id": "0xd3adb00b5",
+				"name": "Employee NFC key",
+				"platform": "apple",
+				"protocol": "desfire",
+				"watch_count": 1,
+				"design": {"background_color": "#FFFFFF", "label_color": "#111111"}
+			}`))
+		case r.URL.Path == "/v1/console/card-templates/0xd3adb00b5" && r.Method == http.MethodPut:
+			var params models.UpdateTemplateParams
+			json.NewDecoder(r.Body).Decode(&params)
+			calls.Store("update", params)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "0xd3adb00b5"}`))
+		case r.URL.Path == "/v1/console/card-templates" && r.Method == http.MethodPost:
+			calls.Store("create", true)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "0xp4rk1ng"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "not found"}`))
+		}
+	}))
+
+	c, _ := client.NewClient("test-account", "test-secret", client.WithBaseURL(server.URL))
+	return server, services.NewConsoleService(c), calls
+}
+
+func loadTestState() *State {
+	state := NewState()
+	state.Templates["employee-badge"] = StateEntry{ID: "0xd3adb00b5"}
+	state.Templates["retired"] = StateEntry{ID: "0xr3t1r3d"}
+	return state
+}
+
+func TestApplier_Plan(t *testing.T) {
+	server, console, calls := setupTemplateConfigTestServer()
+	defer server.Close()
+
+	cfg, err := LoadConfig(strings.NewReader(testConfig))
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	applier, _ := NewApplier(console)
+
+	report, err := applier.Plan(context.Background(), cfg, loadTestState())
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+
+	actions := make(map[string]string)
+	for _, result := range report.Results {
+		actions[result.Key] = result.Action
+	}
+	want := map[string]string{
+		"employee-badge": ActionUpdate,
+		"parking":        ActionCreate,
+		"retired":        ActionOrphaned,
+	}
+	for key, action := range want {
+		if actions[key] != action {
+			t.Errorf("Plan() %s action = %v, want %v", key, actions[key], action)
+		}
+	}
+	if len(report.Results[0].Drift) != 2 {
+		t.Errorf("Plan() employee-badge drift = %+v, want watch_count and background_color", report.Results[0].Drift)
+	}
+
+	if _, ok := calls.Load("create"); ok {
+		t.Error("Plan() created a template")
+	}
+	if _, ok := calls.Load("update"); ok {
+		t.Error("Plan() updated a template")
+	}
+}
+
+func TestApplier_Apply(t *testing.T) {
+	server, console, calls := setupTemplateConfigTestServer()
+	defer server.Close()
+
+	cfg, _ := LoadConfig(strings.NewReader(testConfig))
+	applier, _ := NewApplier(console)
+	state := loadTestState()
+
+	if _, err := applier.Apply(context.Background(), cfg, state); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	if state.Templates["parking"].ID != "0xp4rk1ng" {
+		t.Errorf("Apply() state parking ID = %v, want %v", state.Templates["parking"].ID, "0xp4rk1ng")
+	}
+
+	update, ok := calls.Load("update")
+	if !ok {
+		t.Fatal("Apply() expected template update")
+	}
+	params := update.(models.UpdateTemplateParams)
+	if params.WatchCount != 2 {
+		t.Errorf("Apply() update WatchCount = %v, want %v", params.WatchCount, 2)
+	}
+	if params.Design == nil || params.Design.BackgroundColor != "#000000" || params.Design.LabelColor != "#111111" {
+		t.Errorf("Apply() update Design = %+v, want merged design", params.Design)
+	}
+}
+
+func TestState_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	state, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("LoadState() on missing file error = %v", err)
+	}
+	state.Templates["employee-badge"] = StateEntry{ID: "0xd3adb00b5"}
+	if err := state.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	if loaded.Templates["employee-badge"].ID != "0xd3adb00b5" {
+		t.Errorf("LoadState() employee-badge ID = %v, want %v", loaded.Templates["employee-badge"].ID, "0xd3adb00b5")
+	}
+}
+
+func TestLoadConfig_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"Missing key", `{"templates": [{"name": "Badge"}]}`},
+		{"Duplicate key", `{"templates": [{"key": "a", "name": "A"}, {"key": "a", "name": "B"}]}`},
+		{"Unknown field", `{"templates": [{"key": "a", "name": "A", "colour": "red"}]}`},
+		{"Unknown platform", `{"templates": [{"key": "a", "name": "A", "platform": "kiosk"}]}`},
+		{"Incompatible protocol", `{"templates": [{"key": "a", "name": "A", "platform": "apple", "protocol": "smart_tap"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadConfig(strings.NewReader(tt.data)); err == nil {
+				t.Error("LoadConfig() expected error")
+			}
+		})
+	}
+}
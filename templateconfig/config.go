@@ -0,0 +1,147 @@
+// Package templateconfig manages card templates declaratively. Templates are
+// described in a JSON config file and matched to existing templates by a
+// stable key recorded in a state file, so the same config can be applied to
+// staging and production accounts.
+package templateconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/Access-Grid/accessgrid-go/internal/atomicfile"
+	"github.com/Access-Grid/accessgrid-go/models"
+)
+
+// TemplateSpec is the desired state of one template. Empty string fields
+// are not managed: they are neither compared nor changed.
+type TemplateSpec struct {
+	// Key identifies the template across accounts and environments
+	Key string `json:"key"`
+	models.CreateTemplateParams
+}
+
+// Config is a set of templates to manage
+type Config struct {
+	Templates []TemplateSpec `json:"templates"`
+}
+
+// LoadConfig decodes a config and checks that every template has a unique
+// key and a name
+func LoadConfig(r io.Reader) (*Config, error) {
+	var cfg Config
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("error decoding template config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// LoadConfigFile reads a config from a JSON file
+func LoadConfigFile(path string) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening template config: %w", err)
+	}
+	defer f.Close()
+	return LoadConfig(f)
+}
+
+// Validate checks that every template has a unique key and a name, and
+// that any platform, use case and protocol it sets are known and compatible
+func (c *Config) Validate() error {
+	seen := make(map[string]bool, len(c.Templates))
+	for i, spec := range c.Templates {
+		if spec.Key == "" {
+			return fmt.Errorf("template %d: key is required", i)
+		}
+		if seen[spec.Key] {
+			return fmt.Errorf("template %d: duplicate key %q", i, spec.Key)
+		}
+		seen[spec.Key] = true
+		if spec.Name == "" {
+			return fmt.Errorf("template %q: name is required", spec.Key)
+		}
+		if spec.Platform != "" && !spec.Platform.Valid() {
+			return fmt.Errorf("template %q: unknown platform %q", spec.Key, spec.Platform)
+		}
+		if spec.UseCase != "" && !spec.UseCase.Valid() {
+			return fmt.Errorf("template %q: unknown use case %q", spec.Key, spec.UseCase)
+		}
+		if spec.Protocol != "" && !spec.Protocol.Valid() {
+			return fmt.Errorf("template %q: unknown protocol %q", spec.Key, spec.Protocol)
+		}
+		if spec.Platform != "" && spec.Protocol != "" && !spec.Platform.Supports(spec.Protocol) {
+			return fmt.Errorf("template %q: protocol %q is not supported on %s", spec.Key, spec.Protocol, spec.Platform)
+		}
+	}
+	return nil
+}
+
+// StateEntry records the template a config key was applied to
+type StateEntry struct {
+	ID        string    `json:"id"`
+	AppliedAt time.Time `json:"applied_at"`
+}
+
+// State maps config keys to the IDs of the templates they manage in one
+// account. Keep one state file per account.
+type State struct {
+	Templates map[string]StateEntry `json:"templates"`
+}
+
+// NewState creates an empty State
+func NewState() *State {
+	return &State{Templates: make(map[string]StateEntry)}
+}
+
+// LoadState reads a state file. A missing file yields an empty State.
+func LoadState(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return NewState(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading template state: %w", err)
+	}
+
+	state := NewState()
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("error parsing template state: %w", err)
+	}
+	if state.Templates == nil {
+		state.Templates = make(map[string]StateEntry)
+	}
+	return state, nil
+}
+
+// Save writes the state to a file. The file is replaced atomically, so a
+// crash never leaves a truncated state behind.
+func (s *State) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding template state: %w", err)
+	}
+	if err := atomicfile.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("error writing template state: %w", err)
+	}
+	return nil
+}
+
+// Keys returns the managed keys in sorted order
+func (s *State) Keys() []string {
+	keys := make([]string, 0, len(s.Templates))
+	for key := range s.Templates {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,258 @@
+package templatedesign
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Access-Grid/accessgrid-go/models"
+
+	// Register decoders for the formats accepted by Load
+	_ "image/gif"
+)
+
+// Asset identifies one of the images in a TemplateDesign
+type Asset string
+
+// Design assets
+const (
+	AssetBackground Asset = "background_image"
+	AssetLogo       Asset = "logo_image"
+	AssetIcon       Asset = "icon_image"
+)
+
+// Assets lists every design asset in the order they are reported
+var Assets = []Asset{AssetBackground, AssetLogo, AssetIcon}
+
+// maxSourceSize caps how much image data Load reads
+const maxSourceSize = 32 << 20
+
+// maxSourcePixels caps the canvas size Load will decode. A small file can
+// declare a huge canvas, so this is checked from the header before the
+// pixels are allocated.
+const maxSourcePixels = 24 << 20
+
+// Constraint describes the size an asset must have on a platform
+type Constraint struct {
+	Width  int
+	Height int
+	// Exact requires the image to be exactly Width x Height. Otherwise it
+	// must fit within those dimensions.
+	Exact    bool
+	MaxBytes int
+}
+
+// Fits reports whether an image of the given bounds satisfies the
+// constraint's dimensions
+func (c Constraint) Fits(bounds image.Rectangle) bool {
+	w, h := bounds.Dx(), bounds.Dy()
+	if c.Exact {
+		return w == c.Width && h == c.Height
+	}
+	return w <= c.Width && h <= c.Height
+}
+
+func (c Constraint) String() string {
+	if c.Exact {
+		return fmt.Sprintf("exactly %dx%d", c.Width, c.Height)
+	}
+	return fmt.Sprintf("at most %dx%d", c.Width, c.Height)
+}
+
+// platformConstraints holds the asset constraints for each platform, based
+// on the wallet providers' published image guidelines
+var platformConstraints = map[models.TemplatePlatform]map[Asset]Constraint{
+	models.TemplatePlatformApple: {
+		AssetBackground: {Width: 360, Height: 440, Exact: true, MaxBytes: 1 << 20},
+		AssetLogo:       {Width: 320, Height: 100, MaxBytes: 512 << 10},
+		AssetIcon:       {Width: 58, Height: 58, Exact: true, MaxBytes: 256 << 10},
+	},
+	models.TemplatePlatformGoogle: {
+		AssetBackground: {Width: 1032, Height: 336, Exact: true, MaxBytes: 1 << 20},
+		AssetLogo:       {Width: 660, Height: 660, Exact: true, MaxBytes: 512 << 10},
+		AssetIcon:       {Width: 660, Height: 660, Exact: true, MaxBytes: 512 << 10},
+	},
+}
+
+// ConstraintFor returns the constraint for an asset on a platform. Android
+// templates use the Google constraints.
+func ConstraintFor(platform models.TemplatePlatform, asset Asset) (Constraint, bool) {
+	c, ok := platformConstraints[platform.Canonical()][asset]
+	return c, ok
+}
+
+// Load decodes a PNG, JPEG or GIF image from r. Images over 32 MiB or
+// with more than about 25 million pixels are rejected.
+func Load(r io.Reader) (image.Image, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxSourceSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("error reading image: %w", err)
+	}
+	if len(data) > maxSourceSize {
+		return nil, fmt.Errorf("image exceeds %d bytes", maxSourceSize)
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding image: %w", err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || int64(cfg.Width)*int64(cfg.Height) > maxSourcePixels {
+		return nil, fmt.Errorf("image is %dx%d, limit is %d pixels", cfg.Width, cfg.Height, maxSourcePixels)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding image: %w", err)
+	}
+	return img, nil
+}
+
+// LoadFile decodes a PNG, JPEG or GIF image from a file
+func LoadFile(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Load(f)
+}
+
+// DecodeImage decodes a base64 design image as stored in a TemplateDesign.
+// Data URIs are accepted as well.
+func DecodeImage(encoded string) (image.Image, error) {
+	if strings.HasPrefix(encoded, "data:") {
+		_, data, ok := strings.Cut(encoded, ";base64,")
+		if !ok {
+			return nil, errors.New("data URI is not base64 encoded")
+		}
+		encoded = data
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("not valid base64: %w", err)
+	}
+	return Load(bytes.NewReader(data))
+}
+
+// Fit scales img to satisfy c, preserving its aspect ratio. For exact
+// constraints the scaled image is centered on a canvas of the required
+// size filled with pad.
+func Fit(img image.Image, c Constraint, pad color.Color) image.Image {
+	b := img.Bounds()
+	if c.Fits(b) && b.Min == (image.Point{}) {
+		return img
+	}
+
+	// Scale to fit within the box; images already within a non-exact
+	// constraint are never enlarged
+	scale := min(float64(c.Width)/float64(b.Dx()), float64(c.Height)/float64(b.Dy()))
+	if !c.Exact {
+		scale = min(scale, 1)
+	}
+	w := max(1, int(float64(b.Dx())*scale+0.5))
+	h := max(1, int(float64(b.Dy())*scale+0.5))
+	scaled := Resize(img, w, h)
+
+	if !c.Exact {
+		return scaled
+	}
+
+	canvas := image.NewRGBA(image.Rect(0, 0, c.Width, c.Height))
+	draw.Draw(canvas, canvas.Bounds(), image.NewUniform(pad), image.Point{}, draw.Src)
+	offset := image.Pt((c.Width-w)/2, (c.Height-h)/2)
+	draw.Draw(canvas, scaled.Bounds().Add(offset), scaled, image.Point{}, draw.Over)
+	return canvas
+}
+
+// Resize scales img to w x h by averaging the source pixels that cover each
+// destination pixel
+func Resize(img image.Image, w, h int) *image.RGBA {
+	b := img.Bounds()
+	src := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(src, src.Bounds(), img, b.Min, draw.Src)
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+	for y := 0; y < h; y++ {
+		y0 := y * sh / h
+		y1 := max(y0+1, (y+1)*sh/h)
+		for x := 0; x < w; x++ {
+			x0 := x * sw / w
+			x1 := max(x0+1, (x+1)*sw/w)
+
+			var r, g, bl, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += uint32(p[0])
+					g += uint32(p[1])
+					bl += uint32(p[2])
+					a += uint32(p[3])
+					n++
+				}
+			}
+
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(bl / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// Encode encodes img for the API and returns it base64 encoded. Images are
+// written as PNG; opaque images that exceed c.MaxBytes as PNG fall back to
+// JPEG at decreasing quality.
+func Encode(img image.Image, c Constraint) (string, error) {
+	var buf bytes.Buffer
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := encoder.Encode(&buf, img); err != nil {
+		return "", fmt.Errorf("error encoding image: %w", err)
+	}
+
+	if c.MaxBytes > 0 && buf.Len() > c.MaxBytes && opaque(img) {
+		for _, quality := range []int{90, 80, 70, 60, 50} {
+			buf.Reset()
+			if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+				return "", fmt.Errorf("error encoding image: %w", err)
+			}
+			if buf.Len() <= c.MaxBytes {
+				break
+			}
+		}
+	}
+
+	if c.MaxBytes > 0 && buf.Len() > c.MaxBytes {
+		return "", fmt.Errorf("encoded image is %d bytes, limit is %d", buf.Len(), c.MaxBytes)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// opaque reports whether every pixel of img is fully opaque
+func opaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if _, _, _, a := img.At(x, y).RGBA(); a != 0xffff {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,204 @@
+package templatedesign
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Access-Grid/accessgrid-go/models"
+)
+
+func solid(w, h int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := 0; i < len(img.Pix); i += 4 {
+		r, g, b, a := c.RGBA()
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = uint8(r>>8), uint8(g>>8), uint8(b>>8), uint8(a>>8)
+	}
+	return img
+}
+
+func writePNG(t *testing.T, img image.Image) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "image.png")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, img); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func decodeBase64(t *testing.T, encoded string) image.Image {
+	t.Helper()
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatalf("invalid base64: %v", err)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("invalid image: %v", err)
+	}
+	return img
+}
+
+func TestFit(t *testing.T) {
+	red := color.RGBA{0xff, 0, 0, 0xff}
+
+	t.Run("Exact pads to size", func(t *testing.T) {
+		c := Constraint{Width: 100, Height: 100, Exact: true}
+		fitted := Fit(solid(200, 100, red), c, color.White)
+
+		if fitted.Bounds() != image.Rect(0, 0, 100, 100) {
+			t.Fatalf("Fit() bounds = %v", fitted.Bounds())
+		}
+		if got := color.RGBAModel.Convert(fitted.At(50, 50)); got != red {
+			t.Errorf("center = %v, want red", got)
+		}
+		if got := color.RGBAModel.Convert(fitted.At(50, 5)); got != (color.RGBA{0xff, 0xff, 0xff, 0xff}) {
+			t.Errorf("padding = %v, want white", got)
+		}
+	})
+
+	t.Run("Within scales down preserving aspect", func(t *testing.T) {
+		fitted := Fit(solid(640, 100, red), Constraint{Width: 320, Height: 100}, color.Transparent)
+		if fitted.Bounds() != image.Rect(0, 0, 320, 50) {
+			t.Errorf("Fit() bounds = %v, want 320x50", fitted.Bounds())
+		}
+	})
+
+	t.Run("Within never enlarges", func(t *testing.T) {
+		img := solid(10, 10, red)
+		if fitted := Fit(img, Constraint{Width: 320, Height: 100}, color.Transparent); fitted != image.Image(img) {
+			t.Errorf("Fit() changed an image that already fits")
+		}
+	})
+}
+
+func TestLoad_RejectsHugeCanvas(t *testing.T) {
+	var buf bytes.Buffer
+	png.Encode(&buf, solid(1, 1, color.Black))
+	data := buf.Bytes()
+
+	// Rewrite the IHDR chunk to declare a 100000x100000 canvas
+	binary.BigEndian.PutUint32(data[16:], 100000)
+	binary.BigEndian.PutUint32(data[20:], 100000)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+
+	if _, err := Load(bytes.NewReader(data)); err == nil || !strings.Contains(err.Error(), "limit") {
+		t.Errorf("Load() error = %v, want pixel limit error", err)
+	}
+}
+
+func TestEncode_SizeLimit(t *testing.T) {
+	noise := image.NewRGBA(image.Rect(0, 0, 200, 200))
+	rand.New(rand.NewSource(1)).Read(noise.Pix)
+	for i := 3; i < len(noise.Pix); i += 4 {
+		noise.Pix[i] = 0xff
+	}
+
+	// Opaque images fall back to JPEG to meet the limit
+	encoded, err := Encode(noise, Constraint{MaxBytes: 100 << 10})
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if data, _ := base64.StdEncoding.DecodeString(encoded); len(data) > 100<<10 {
+		t.Errorf("Encode() produced %d bytes", len(data))
+	}
+
+	if _, err := Encode(noise, Constraint{MaxBytes: 100}); err == nil {
+		t.Error("Encode() error = nil, want size limit error")
+	}
+}
+
+func TestPipeline_Apply(t *testing.T) {
+	pipeline, err := NewPipeline("apple", WithPadColor(color.White))
+	if err != nil {
+		t.Fatalf("NewPipeline() error = %v", err)
+	}
+
+	notAnImage := filepath.Join(t.TempDir(), "logo.png")
+	os.WriteFile(notAnImage, []byte("not an image"), 0o644)
+
+	design := models.TemplateDesign{
+		BackgroundColor: "#fff",
+		LabelColor:      "black",
+		LogoImage:       "unchanged",
+	}
+	err = pipeline.Apply(&design, map[Asset]string{
+		AssetBackground: writePNG(t, solid(720, 720, color.Black)),
+		AssetLogo:       notAnImage,
+		AssetIcon:       writePNG(t, solid(29, 29, color.Black)),
+	})
+
+	var verr *models.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Apply() error = %v, want *ValidationError", err)
+	}
+	fields := map[string]bool{}
+	for _, fe := range verr.Errors {
+		fields[fe.Field] = true
+	}
+	if len(verr.Errors) != 2 || !fields["logo_image"] || !fields["label_color"] {
+		t.Errorf("Apply() problems = %+v, want logo_image and label_color", verr.Errors)
+	}
+
+	if b := decodeBase64(t, design.BackgroundImage).Bounds(); b.Dx() != 360 || b.Dy() != 440 {
+		t.Errorf("background = %v, want 360x440", b)
+	}
+	if b := decodeBase64(t, design.IconImage).Bounds(); b.Dx() != 58 || b.Dy() != 58 {
+		t.Errorf("icon = %v, want 58x58", b)
+	}
+	if design.LogoImage != "unchanged" {
+		t.Errorf("logo = %q, want failed asset left unchanged", design.LogoImage)
+	}
+	if design.BackgroundColor != "#FFFFFF" {
+		t.Errorf("background color = %q, want normalized", design.BackgroundColor)
+	}
+}
+
+func TestPipeline_WithoutAutoFit(t *testing.T) {
+	pipeline, _ := NewPipeline("google", WithoutAutoFit())
+
+	var buf bytes.Buffer
+	png.Encode(&buf, solid(100, 100, color.Black))
+	if _, err := pipeline.Prepare(AssetLogo, &buf); err == nil {
+		t.Error("Prepare() error = nil, want dimension error")
+	}
+}
+
+func TestPipeline_Validate(t *testing.T) {
+	pipeline, _ := NewPipeline("android")
+
+	var buf bytes.Buffer
+	png.Encode(&buf, solid(660, 660, color.Black))
+	good := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	if err := pipeline.Validate(models.TemplateDesign{LogoImage: good, IconImage: good}); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	err := pipeline.Validate(models.TemplateDesign{BackgroundImage: good, IconImage: "%%%"})
+	var verr *models.ValidationError
+	if !errors.As(err, &verr) || len(verr.Errors) != 2 {
+		t.Errorf("Validate() error = %v, want problems for background and icon", err)
+	}
+}
+
+func TestNewPipeline_UnknownPlatform(t *testing.T) {
+	if _, err := NewPipeline("blackberry"); err == nil {
+		t.Error("NewPipeline() error = nil, want unsupported platform")
+	}
+}
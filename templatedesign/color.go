@@ -0,0 +1,46 @@
+// Package templatedesign prepares and validates the images and colors of a
+// TemplateDesign before it is sent to the API
+package templatedesign
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// ParseHexColor parses a "#RRGGBB" or "#RGB" color. The leading "#" is
+// required.
+func ParseHexColor(s string) (color.RGBA, error) {
+	if !strings.HasPrefix(s, "#") {
+		return color.RGBA{}, fmt.Errorf("color %q must start with #", s)
+	}
+	hex := s[1:]
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return color.RGBA{}, fmt.Errorf("color %q must have 3 or 6 hex digits", s)
+	}
+
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("color %q is not valid hex", s)
+	}
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}, nil
+}
+
+// FormatHexColor formats c as "#RRGGBB", ignoring alpha
+func FormatHexColor(c color.Color) string {
+	rgba := color.RGBAModel.Convert(c).(color.RGBA)
+	return fmt.Sprintf("#%02X%02X%02X", rgba.R, rgba.G, rgba.B)
+}
+
+// NormalizeHexColor validates s and returns it in "#RRGGBB" form
+func NormalizeHexColor(s string) (string, error) {
+	c, err := ParseHexColor(s)
+	if err != nil {
+		return "", err
+	}
+	return FormatHexColor(c), nil
+}
@@ -0,0 +1,41 @@
+package templatedesign
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestParseHexColor(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    color.RGBA
+		wantErr bool
+	}{
+		{in: "#FFFFFF", want: color.RGBA{0xff, 0xff, 0xff, 0xff}},
+		{in: "#1a2B3c", want: color.RGBA{0x1a, 0x2b, 0x3c, 0xff}},
+		{in: "#0f8", want: color.RGBA{0x00, 0xff, 0x88, 0xff}},
+		{in: "FFFFFF", wantErr: true},
+		{in: "#FFFF", wantErr: true},
+		{in: "#GGGGGG", wantErr: true},
+		{in: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseHexColor(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseHexColor(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseHexColor(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeHexColor(t *testing.T) {
+	got, err := NormalizeHexColor("#abc")
+	if err != nil || got != "#AABBCC" {
+		t.Errorf("NormalizeHexColor(#abc) = %q, %v", got, err)
+	}
+}
@@ -0,0 +1,278 @@
+package templatedesign
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/Access-Grid/accessgrid-go/models"
+)
+
+// WCAG 2 minimum contrast ratios
+const (
+	// MinContrastAA is the minimum for normal text at level AA
+	MinContrastAA = 4.5
+	// MinContrastAALarge is the minimum for large text at level AA
+	MinContrastAALarge = 3.0
+	// MinContrastAAA is the minimum for normal text at level AAA
+	MinContrastAAA = 7.0
+)
+
+// RelativeLuminance returns the WCAG relative luminance of c, from 0 for
+// black to 1 for white
+func RelativeLuminance(c color.Color) float64 {
+	rgba := color.RGBAModel.Convert(c).(color.RGBA)
+	channel := func(v uint8) float64 {
+		s := float64(v) / 255
+		if s <= 0.04045 {
+			return s / 12.92
+		}
+		return math.Pow((s+0.055)/1.055, 2.4)
+	}
+	return 0.2126*channel(rgba.R) + 0.7152*channel(rgba.G) + 0.0722*channel(rgba.B)
+}
+
+// ContrastRatio returns the WCAG contrast ratio between two colors, from 1
+// for identical colors to 21 for black on white
+func ContrastRatio(a, b color.Color) float64 {
+	la, lb := RelativeLuminance(a), RelativeLuminance(b)
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// DominantColor returns the most common color in img, with each channel
+// quantized to 16 levels and averaged within the winning bucket.
+// Transparent pixels are ignored; ok is false if every pixel is
+// transparent.
+func DominantColor(img image.Image) (c color.RGBA, ok bool) {
+	type sum struct{ r, g, b, n uint64 }
+	buckets := make(map[uint16]*sum)
+
+	// Sample large images on a grid rather than visiting every pixel
+	bounds := img.Bounds()
+	step := max(1, int(math.Sqrt(float64(bounds.Dx()*bounds.Dy())/65536)))
+
+	var best *sum
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += step {
+		for x := bounds.Min.X; x < bounds.Max.X; x += step {
+			px := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if px.A < 0x80 {
+				continue
+			}
+			key := uint16(px.R>>4)<<8 | uint16(px.G>>4)<<4 | uint16(px.B>>4)
+			s, found := buckets[key]
+			if !found {
+				s = &sum{}
+				buckets[key] = s
+			}
+			s.r += uint64(px.R)
+			s.g += uint64(px.G)
+			s.b += uint64(px.B)
+			s.n++
+			if best == nil || s.n > best.n {
+				best = s
+			}
+		}
+	}
+
+	if best == nil {
+		return color.RGBA{}, false
+	}
+	return color.RGBA{R: uint8(best.r / best.n), G: uint8(best.g / best.n), B: uint8(best.b / best.n), A: 0xff}, true
+}
+
+// ContrastCheck is the contrast between a label color and a background
+type ContrastCheck struct {
+	Foreground string  `json:"foreground"`
+	Background string  `json:"background"`
+	Ratio      float64 `json:"ratio"`
+	Passed     bool    `json:"passed"`
+}
+
+// ContrastReport lists the contrast checks made for a design
+type ContrastReport struct {
+	MinRatio float64         `json:"min_ratio"`
+	Checks   []ContrastCheck `json:"checks"`
+}
+
+// Passed reports whether every check met the minimum ratio
+func (r *ContrastReport) Passed() bool {
+	for _, c := range r.Checks {
+		if !c.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Failures returns the checks below the minimum ratio
+func (r *ContrastReport) Failures() []ContrastCheck {
+	var failures []ContrastCheck
+	for _, c := range r.Checks {
+		if !c.Passed {
+			failures = append(failures, c)
+		}
+	}
+	return failures
+}
+
+// CheckContrast compares LabelColor and LabelSecondaryColor against
+// BackgroundColor and, if the design has a background image, against the
+// image's dominant color. Empty colors are skipped.
+func CheckContrast(design models.TemplateDesign, minRatio float64) (*ContrastReport, error) {
+	type namedColor struct {
+		name  string
+		color color.RGBA
+	}
+
+	parse := func(field, value string) (*namedColor, error) {
+		if value == "" {
+			return nil, nil
+		}
+		c, err := ParseHexColor(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", field, err)
+		}
+		return &namedColor{field, c}, nil
+	}
+
+	var backgrounds, foregrounds []namedColor
+	bg, err := parse("background_color", design.BackgroundColor)
+	if err != nil {
+		return nil, err
+	}
+	if bg != nil {
+		backgrounds = append(backgrounds, *bg)
+	}
+	if design.BackgroundImage != "" {
+		img, err := DecodeImage(design.BackgroundImage)
+		if err != nil {
+			return nil, fmt.Errorf("background_image: %w", err)
+		}
+		if dominant, ok := DominantColor(img); ok {
+			backgrounds = append(backgrounds, namedColor{"background_image", dominant})
+		}
+	}
+
+	for _, field := range []struct{ name, value string }{
+		{"label_color", design.LabelColor},
+		{"label_secondary_color", design.LabelSecondaryColor},
+	} {
+		fg, err := parse(field.name, field.value)
+		if err != nil {
+			return nil, err
+		}
+		if fg != nil {
+			foregrounds = append(foregrounds, *fg)
+		}
+	}
+
+	report := &ContrastReport{MinRatio: minRatio}
+	for _, fg := range foregrounds {
+		for _, bg := range backgrounds {
+			ratio := ContrastRatio(fg.color, bg.color)
+			report.Checks = append(report.Checks, ContrastCheck{
+				Foreground: fg.name,
+				Background: bg.name,
+				Ratio:      math.Round(ratio*100) / 100,
+				Passed:     ratio >= minRatio,
+			})
+		}
+	}
+	return report, nil
+}
+
+// SuggestLabelColor returns the color closest to brand that has at least
+// minRatio contrast against background. It moves brand toward black or
+// white, whichever can reach the ratio with the smaller change, and
+// returns black or white outright if neither can.
+func SuggestLabelColor(brand, background color.Color, minRatio float64) color.RGBA {
+	base := color.RGBAModel.Convert(brand).(color.RGBA)
+	base.A = 0xff
+	if ContrastRatio(base, background) >= minRatio {
+		return base
+	}
+
+	black := color.RGBA{A: 0xff}
+	white := color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+
+	var best color.RGBA
+	bestT := math.Inf(1)
+	for _, target := range []color.RGBA{black, white} {
+		if ContrastRatio(target, background) < minRatio {
+			continue
+		}
+		// Binary search for the smallest blend toward target that passes
+		lo, hi := 0.0, 1.0
+		for i := 0; i < 20; i++ {
+			mid := (lo + hi) / 2
+			if ContrastRatio(blend(base, target, mid), background) >= minRatio {
+				hi = mid
+			} else {
+				lo = mid
+			}
+		}
+		if hi < bestT {
+			bestT = hi
+			best = blend(base, target, hi)
+		}
+	}
+
+	if math.IsInf(bestT, 1) {
+		if ContrastRatio(black, background) >= ContrastRatio(white, background) {
+			return black
+		}
+		return white
+	}
+	return best
+}
+
+// blend mixes a toward b by t in [0, 1]
+func blend(a, b color.RGBA, t float64) color.RGBA {
+	mix := func(x, y uint8) uint8 {
+		return uint8(math.Round(float64(x) + (float64(y)-float64(x))*t))
+	}
+	return color.RGBA{R: mix(a.R, b.R), G: mix(a.G, b.G), B: mix(a.B, b.B), A: 0xff}
+}
+
+// Palette is a set of design colors derived from a brand color
+type Palette struct {
+	BackgroundColor     string `json:"background_color"`
+	LabelColor          string `json:"label_color"`
+	LabelSecondaryColor string `json:"label_secondary_color"`
+}
+
+// Apply sets the palette's colors on design
+func (p Palette) Apply(design *models.TemplateDesign) {
+	design.BackgroundColor = p.BackgroundColor
+	design.LabelColor = p.LabelColor
+	design.LabelSecondaryColor = p.LabelSecondaryColor
+}
+
+// SuggestPalette builds a palette that uses brand as the background, with
+// the higher contrast of black or white as the label color and a brand
+// tinted secondary label color that still meets minRatio
+func SuggestPalette(brand color.Color, minRatio float64) Palette {
+	background := color.RGBAModel.Convert(brand).(color.RGBA)
+	background.A = 0xff
+
+	black := color.RGBA{A: 0xff}
+	white := color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	label := white
+	if ContrastRatio(black, background) > ContrastRatio(white, background) {
+		label = black
+	}
+
+	// Start the secondary color halfway between the label and the brand so
+	// it reads as related to both, then adjust it until it passes
+	secondary := SuggestLabelColor(blend(label, background, 0.5), background, minRatio)
+
+	return Palette{
+		BackgroundColor:     FormatHexColor(background),
+		LabelColor:          FormatHexColor(label),
+		LabelSecondaryColor: FormatHexColor(secondary),
+	}
+}
@@ -0,0 +1,154 @@
+package templatedesign
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"testing"
+
+	"github.com/Access-Grid/accessgrid-go/models"
+)
+
+func TestContrastRatio(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want float64
+	}{
+		{"Black on white", "#000000", "#FFFFFF", 21},
+		{"Identical colors", "#336699", "#336699", 1},
+		{"Gray on white", "#767676", "#FFFFFF", 4.54},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, _ := ParseHexColor(tt.a)
+			b, _ := ParseHexColor(tt.b)
+			if got := ContrastRatio(a, b); math.Abs(got-tt.want) > 0.01 {
+				t.Errorf("ContrastRatio(%s, %s) = %.3f, want %.2f", tt.a, tt.b, got, tt.want)
+			}
+			if ContrastRatio(a, b) != ContrastRatio(b, a) {
+				t.Error("ContrastRatio() is not symmetric")
+			}
+		})
+	}
+}
+
+func TestDominantColor(t *testing.T) {
+	img := solid(10, 10, color.RGBA{0x10, 0x20, 0x30, 0xff})
+	for x := 0; x < 3; x++ {
+		img.Set(x, 0, color.White)
+	}
+
+	got, ok := DominantColor(img)
+	if !ok || got != (color.RGBA{0x10, 0x20, 0x30, 0xff}) {
+		t.Errorf("DominantColor() = %v, %v", got, ok)
+	}
+
+	if _, ok := DominantColor(image.NewRGBA(image.Rect(0, 0, 4, 4))); ok {
+		t.Error("DominantColor() ok = true for a transparent image")
+	}
+}
+
+func TestCheckContrast(t *testing.T) {
+	var buf bytes.Buffer
+	png.Encode(&buf, solid(20, 20, color.Black))
+
+	design := models.TemplateDesign{
+		BackgroundColor:     "#FFFFFF",
+		LabelColor:          "#000000",
+		LabelSecondaryColor: "#AAAAAA",
+		BackgroundImage:     base64.StdEncoding.EncodeToString(buf.Bytes()),
+	}
+
+	report, err := CheckContrast(design, MinContrastAA)
+	if err != nil {
+		t.Fatalf("CheckContrast() error = %v", err)
+	}
+	if len(report.Checks) != 4 {
+		t.Fatalf("CheckContrast() checks = %+v, want 4", report.Checks)
+	}
+	if report.Passed() {
+		t.Error("Passed() = true, want failures")
+	}
+
+	failed := map[[2]string]bool{}
+	for _, c := range report.Failures() {
+		failed[[2]string{c.Foreground, c.Background}] = true
+	}
+	want := map[[2]string]bool{
+		{"label_color", "background_image"}:           true,
+		{"label_secondary_color", "background_color"}: true,
+	}
+	if len(failed) != len(want) {
+		t.Errorf("Failures() = %+v", report.Failures())
+	}
+	for pair := range want {
+		if !failed[pair] {
+			t.Errorf("Failures() missing %v", pair)
+		}
+	}
+
+	if _, err := CheckContrast(models.TemplateDesign{LabelColor: "black"}, MinContrastAA); err == nil {
+		t.Error("CheckContrast() error = nil for invalid color")
+	}
+}
+
+func TestSuggestLabelColor(t *testing.T) {
+	white := color.RGBA{0xff, 0xff, 0xff, 0xff}
+	brand := color.RGBA{0x66, 0xaa, 0xff, 0xff}
+
+	got := SuggestLabelColor(brand, white, MinContrastAA)
+	if ratio := ContrastRatio(got, white); ratio < MinContrastAA {
+		t.Errorf("SuggestLabelColor() = %v with ratio %.2f", got, ratio)
+	}
+	if got.B <= got.R {
+		t.Errorf("SuggestLabelColor() = %v, want it to stay blue", got)
+	}
+
+	// A passing brand color is returned unchanged
+	navy := color.RGBA{0x00, 0x00, 0x80, 0xff}
+	if got := SuggestLabelColor(navy, white, MinContrastAA); got != navy {
+		t.Errorf("SuggestLabelColor() = %v, want %v unchanged", got, navy)
+	}
+}
+
+func TestSuggestPalette(t *testing.T) {
+	for _, brand := range []string{"#0A2463", "#FFD166", "#E63946", "#808080"} {
+		t.Run(brand, func(t *testing.T) {
+			c, _ := ParseHexColor(brand)
+			palette := SuggestPalette(c, MinContrastAA)
+
+			var design models.TemplateDesign
+			palette.Apply(&design)
+			report, err := CheckContrast(design, MinContrastAA)
+			if err != nil {
+				t.Fatalf("CheckContrast() error = %v", err)
+			}
+			if !report.Passed() {
+				t.Errorf("SuggestPalette(%s) = %+v fails: %+v", brand, palette, report.Failures())
+			}
+			if design.BackgroundColor != brand {
+				t.Errorf("BackgroundColor = %s, want %s", design.BackgroundColor, brand)
+			}
+		})
+	}
+}
+
+func TestPipeline_WithMinContrast(t *testing.T) {
+	pipeline, _ := NewPipeline("apple", WithMinContrast(MinContrastAA))
+
+	err := pipeline.Validate(models.TemplateDesign{BackgroundColor: "#FFFFFF", LabelColor: "#EEEEEE"})
+	var verr *models.ValidationError
+	if !errors.As(err, &verr) || len(verr.Errors) != 1 || verr.Errors[0].Field != "label_color" {
+		t.Errorf("Validate() error = %v, want label_color contrast problem", err)
+	}
+
+	if err := pipeline.Validate(models.TemplateDesign{BackgroundColor: "#FFFFFF", LabelColor: "#000000"}); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
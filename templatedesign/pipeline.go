@@ -0,0 +1,218 @@
+package templatedesign
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+
+	"github.com/Access-Grid/accessgrid-go/models"
+)
+
+// Pipeline loads, fits and encodes design assets for one platform
+type Pipeline struct {
+	platform    models.TemplatePlatform
+	pad         color.Color
+	autoFit     bool
+	minContrast float64
+}
+
+// Option allows for customizing the pipeline
+type Option func(*Pipeline)
+
+// WithPadColor sets the color used to pad images that are fitted to exact
+// dimensions. The default is transparent.
+func WithPadColor(c color.Color) Option {
+	return func(p *Pipeline) {
+		p.pad = c
+	}
+}
+
+// WithoutAutoFit makes the pipeline reject images with the wrong
+// dimensions instead of resizing them
+func WithoutAutoFit() Option {
+	return func(p *Pipeline) {
+		p.autoFit = false
+	}
+}
+
+// WithMinContrast makes Apply and Validate also report label colors whose
+// contrast against the background is below ratio. See CheckContrast.
+func WithMinContrast(ratio float64) Option {
+	return func(p *Pipeline) {
+		p.minContrast = ratio
+	}
+}
+
+// NewPipeline creates a Pipeline for a template platform
+func NewPipeline(platform models.TemplatePlatform, options ...Option) (*Pipeline, error) {
+	if _, ok := ConstraintFor(platform, AssetLogo); !ok {
+		return nil, fmt.Errorf("unsupported platform %q", platform)
+	}
+
+	pipeline := &Pipeline{
+		platform: platform,
+		pad:      color.Transparent,
+		autoFit:  true,
+	}
+
+	// Apply any custom options
+	for _, option := range options {
+		option(pipeline)
+	}
+
+	return pipeline, nil
+}
+
+// Prepare decodes an image, fits it to the asset's constraint and returns
+// the base64 value to send in a TemplateDesign
+func (p *Pipeline) Prepare(asset Asset, r io.Reader) (string, error) {
+	img, err := Load(r)
+	if err != nil {
+		return "", err
+	}
+	return p.PrepareImage(asset, img)
+}
+
+// PrepareFile is like Prepare but reads the image from a file
+func (p *Pipeline) PrepareFile(asset Asset, path string) (string, error) {
+	img, err := LoadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return p.PrepareImage(asset, img)
+}
+
+// PrepareImage fits an already decoded image to the asset's constraint and
+// encodes it
+func (p *Pipeline) PrepareImage(asset Asset, img image.Image) (string, error) {
+	c, ok := ConstraintFor(p.platform, asset)
+	if !ok {
+		return "", fmt.Errorf("unknown asset %q", asset)
+	}
+
+	if !c.Fits(img.Bounds()) {
+		if !p.autoFit {
+			return "", fmt.Errorf("image is %dx%d, must be %s", img.Bounds().Dx(), img.Bounds().Dy(), c)
+		}
+		img = Fit(img, c, p.pad)
+	}
+	return Encode(img, c)
+}
+
+// Apply prepares the image files given per asset, stores them in design and
+// normalizes its colors. Every asset is processed even if an earlier one
+// fails; the returned *models.ValidationError lists each failure. Assets
+// that fail are left unchanged in design.
+func (p *Pipeline) Apply(design *models.TemplateDesign, files map[Asset]string) error {
+	verr := &models.ValidationError{}
+	for _, asset := range Assets {
+		path, ok := files[asset]
+		if !ok {
+			continue
+		}
+		encoded, err := p.PrepareFile(asset, path)
+		if err != nil {
+			verr.Add(string(asset), err.Error())
+			continue
+		}
+		*designImage(design, asset) = encoded
+	}
+
+	normalizeColors(design, verr)
+	p.checkContrast(*design, verr)
+	return verr.Err()
+}
+
+// Validate checks an already encoded design against the platform's
+// constraints without modifying it
+func (p *Pipeline) Validate(design models.TemplateDesign) error {
+	verr := &models.ValidationError{}
+	for _, asset := range Assets {
+		encoded := *designImage(&design, asset)
+		if encoded == "" {
+			continue
+		}
+		c, _ := ConstraintFor(p.platform, asset)
+		if err := validateEncoded(encoded, c); err != nil {
+			verr.Add(string(asset), err.Error())
+		}
+	}
+
+	normalizeColors(&design, verr)
+	p.checkContrast(design, verr)
+	return verr.Err()
+}
+
+// checkContrast records label colors below the pipeline's minimum
+// contrast. Invalid colors are already reported by normalizeColors.
+func (p *Pipeline) checkContrast(design models.TemplateDesign, verr *models.ValidationError) {
+	if p.minContrast <= 0 {
+		return
+	}
+	report, err := CheckContrast(design, p.minContrast)
+	if err != nil {
+		return
+	}
+	for _, c := range report.Failures() {
+		verr.Add(c.Foreground, fmt.Sprintf("contrast against %s is %.2f:1, need %.2f:1", c.Background, c.Ratio, p.minContrast))
+	}
+}
+
+// validateEncoded checks a base64 image against a constraint
+func validateEncoded(encoded string, c Constraint) error {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return fmt.Errorf("not valid base64: %w", err)
+	}
+	if c.MaxBytes > 0 && len(data) > c.MaxBytes {
+		return fmt.Errorf("image is %d bytes, limit is %d", len(data), c.MaxBytes)
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("error decoding image: %w", err)
+	}
+	if bounds := image.Rect(0, 0, cfg.Width, cfg.Height); !c.Fits(bounds) {
+		return fmt.Errorf("image is %dx%d, must be %s", cfg.Width, cfg.Height, c)
+	}
+	return nil
+}
+
+// normalizeColors rewrites the design's non-empty colors as "#RRGGBB",
+// recording any that are invalid
+func normalizeColors(design *models.TemplateDesign, verr *models.ValidationError) {
+	colors := []struct {
+		field string
+		value *string
+	}{
+		{"background_color", &design.BackgroundColor},
+		{"label_color", &design.LabelColor},
+		{"label_secondary_color", &design.LabelSecondaryColor},
+	}
+	for _, c := range colors {
+		if *c.value == "" {
+			continue
+		}
+		normalized, err := NormalizeHexColor(*c.value)
+		if err != nil {
+			verr.Add(c.field, err.Error())
+			continue
+		}
+		*c.value = normalized
+	}
+}
+
+// designImage returns a pointer to the design field holding an asset
+func designImage(design *models.TemplateDesign, asset Asset) *string {
+	switch asset {
+	case AssetBackground:
+		return &design.BackgroundImage
+	case AssetLogo:
+		return &design.LogoImage
+	default:
+		return &design.IconImage
+	}
+}
@@ -0,0 +1,183 @@
+package visitor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Access-Grid/accessgrid-go/internal/atomicfile"
+)
+
+// Pass states tracked by the registry
+const (
+	StateActive    = "active"
+	StateSuspended = "suspended"
+)
+
+// Pass is a temporary pass recorded in the local registry
+type Pass struct {
+	ID             string    `json:"id"`
+	CardIDs        []string  `json:"card_ids"`
+	URL            string    `json:"install_url,omitempty"`
+	CardTemplateID string    `json:"card_template_id"`
+	FullName       string    `json:"full_name"`
+	Email          string    `json:"email,omitempty"`
+	StartDate      time.Time `json:"start_date"`
+	ExpirationDate time.Time `json:"expiration_date"`
+	State          string    `json:"state"`
+}
+
+// Registry stores the temporary passes issued by an Issuer so they can be
+// swept later
+type Registry interface {
+	Save(pass Pass) error
+	Delete(id string) error
+	List() ([]Pass, error)
+}
+
+// MemoryRegistry is a Registry held in memory. It is lost when the process
+// exits.
+type MemoryRegistry struct {
+	mu     sync.Mutex
+	passes map[string]Pass
+}
+
+// NewMemoryRegistry creates an empty MemoryRegistry
+func NewMemoryRegistry() *MemoryRegistry {
+	return &MemoryRegistry{passes: make(map[string]Pass)}
+}
+
+// Save implements Registry
+func (r *MemoryRegistry) Save(pass Pass) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.passes[pass.ID] = pass
+	return nil
+}
+
+// Delete implements Registry
+func (r *MemoryRegistry) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.passes, id)
+	return nil
+}
+
+// List implements Registry. Passes are ordered by expiration date.
+func (r *MemoryRegistry) List() ([]Pass, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	passes := make([]Pass, 0, len(r.passes))
+	for _, pass := range r.passes {
+		passes = append(passes, pass)
+	}
+	sortPasses(passes)
+	return passes, nil
+}
+
+// FileRegistry is a Registry persisted as a JSON file, so passes issued in
+// one run can be swept by a later one. Writes are only serialized within a
+// process: the file must not be written by several processes at once, or
+// passes saved by one may be lost.
+type FileRegistry struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileRegistry creates a FileRegistry backed by the file at path. The file
+// is created on the first Save.
+func NewFileRegistry(path string) *FileRegistry {
+	return &FileRegistry{path: path}
+}
+
+// Save implements Registry
+func (r *FileRegistry) Save(pass Pass) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	passes, err := r.load()
+	if err != nil {
+		return err
+	}
+	passes[pass.ID] = pass
+	return r.store(passes)
+}
+
+// Delete implements Registry
+func (r *FileRegistry) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	passes, err := r.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := passes[id]; !ok {
+		return nil
+	}
+	delete(passes, id)
+	return r.store(passes)
+}
+
+// List implements Registry. Passes are ordered by expiration date.
+func (r *FileRegistry) List() ([]Pass, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	passes := make([]Pass, 0, len(stored))
+	for _, pass := range stored {
+		passes = append(passes, pass)
+	}
+	sortPasses(passes)
+	return passes, nil
+}
+
+func (r *FileRegistry) load() (map[string]Pass, error) {
+	passes := make(map[string]Pass)
+
+	data, err := os.ReadFile(r.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return passes, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading visitor registry: %w", err)
+	}
+	if err := json.Unmarshal(data, &passes); err != nil {
+		return nil, fmt.Errorf("error parsing visitor registry: %w", err)
+	}
+	return passes, nil
+}
+
+// store writes the registry to a temporary file and renames it into place so
+// a crash never leaves a partially written registry behind
+func (r *FileRegistry) store(passes map[string]Pass) error {
+	data, err := json.MarshalIndent(passes, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding visitor registry: %w", err)
+	}
+
+	if err := atomicfile.WriteFile(r.path, data, 0o600); err != nil {
+		return fmt.Errorf("error writing visitor registry: %w", err)
+	}
+	return nil
+}
+
+func sortPasses(passes []Pass) {
+	sort.Slice(passes, func(i, j int) bool {
+		if passes[i].ExpirationDate.Equal(passes[j].ExpirationDate) {
+			return passes[i].ID < passes[j].ID
+		}
+		return passes[i].ExpirationDate.Before(passes[j].ExpirationDate)
+	})
+}
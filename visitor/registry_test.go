@@ -0,0 +1,46 @@
+package visitor
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileRegistry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "visitors.json")
+	expiry, _ := time.Parse(time.RFC3339, "2023-01-01T17:00:00Z")
+
+	registry := NewFileRegistry(path)
+	if passes, err := registry.List(); err != nil || len(passes) != 0 {
+		t.Fatalf("List() on missing file = %v, %v, want empty", passes, err)
+	}
+
+	if err := registry.Save(Pass{ID: "0xv1s1t", CardIDs: []string{"0xv1s1t"}, ExpirationDate: expiry, State: StateActive}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := registry.Save(Pass{ID: "0xe4rly", ExpirationDate: expiry.Add(-time.Hour), State: StateActive}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// A fresh registry on the same file sees the saved passes
+	passes, err := NewFileRegistry(path).List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(passes) != 2 {
+		t.Fatalf("List() got %v passes, want %v", len(passes), 2)
+	}
+	if passes[0].ID != "0xe4rly" {
+		t.Errorf("List() passes[0].ID = %v, want %v", passes[0].ID, "0xe4rly")
+	}
+	if !passes[1].ExpirationDate.Equal(expiry) {
+		t.Errorf("List() passes[1].ExpirationDate = %v, want %v", passes[1].ExpirationDate, expiry)
+	}
+
+	if err := registry.Delete("0xe4rly"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if passes, _ := registry.List(); len(passes) != 1 {
+		t.Errorf("List() after Delete got %v passes, want %v", len(passes), 1)
+	}
+}
@@ -0,0 +1,230 @@
+// Package visitor issues short-lived passes for visitors and contractors on
+// top of AccessCardsService.Provision, and sweeps them once they expire
+package visitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Access-Grid/accessgrid-go/client"
+	"github.com/Access-Grid/accessgrid-go/models"
+	"github.com/Access-Grid/accessgrid-go/services"
+)
+
+const (
+	// DefaultMaxValidity is the longest validity window an Issuer accepts
+	// unless configured otherwise
+	DefaultMaxValidity = 7 * 24 * time.Hour
+
+	// MetadataTemporary is the metadata key marking a pass as temporary
+	MetadataTemporary = "temporary"
+
+	// MetadataExpiresAt is the metadata key holding the pass's scheduled
+	// expiration in RFC 3339 format
+	MetadataExpiresAt = "temporary_expires_at"
+)
+
+// Action is what the sweeper does to an expired pass
+type Action string
+
+// Supported sweep actions
+const (
+	ActionSuspend Action = "suspend"
+	ActionDelete  Action = "delete"
+)
+
+// Issuer issues temporary passes and revokes them after they expire
+type Issuer struct {
+	cards       *services.AccessCardsService
+	registry    Registry
+	maxValidity time.Duration
+	gracePeriod time.Duration
+	action      Action
+	now         func() time.Time
+}
+
+// Option allows for customizing the issuer
+type Option func(*Issuer)
+
+// WithMaxValidity sets the longest validity window Issue accepts
+func WithMaxValidity(d time.Duration) Option {
+	return func(i *Issuer) {
+		i.maxValidity = d
+	}
+}
+
+// WithGracePeriod delays sweeping a pass until this long after it expires
+func WithGracePeriod(d time.Duration) Option {
+	return func(i *Issuer) {
+		i.gracePeriod = d
+	}
+}
+
+// WithSweepAction sets whether expired passes are suspended or deleted. The
+// default is ActionSuspend.
+func WithSweepAction(action Action) Option {
+	return func(i *Issuer) {
+		i.action = action
+	}
+}
+
+// NewIssuer creates a new Issuer. If registry is nil, passes are tracked in
+// memory only.
+func NewIssuer(cards *services.AccessCardsService, registry Registry, options ...Option) (*Issuer, error) {
+	if cards == nil {
+		return nil, errors.New("access cards service is required")
+	}
+	if registry == nil {
+		registry = NewMemoryRegistry()
+	}
+
+	issuer := &Issuer{
+		cards:       cards,
+		registry:    registry,
+		maxValidity: DefaultMaxValidity,
+		action:      ActionSuspend,
+		now:         time.Now,
+	}
+
+	// Apply any custom options
+	for _, option := range options {
+		option(issuer)
+	}
+
+	if issuer.action != ActionSuspend && issuer.action != ActionDelete {
+		return nil, fmt.Errorf("unsupported sweep action %q", issuer.action)
+	}
+
+	return issuer, nil
+}
+
+// Issue provisions a temporary pass valid for the given duration from
+// params.StartDate, or from now if StartDate is zero. The pass is tagged as
+// temporary in its metadata and recorded in the registry for sweeping.
+func (i *Issuer) Issue(ctx context.Context, params models.ProvisionParams, validity time.Duration) (*Pass, error) {
+	if validity <= 0 {
+		return nil, errors.New("validity must be positive")
+	}
+	if validity > i.maxValidity {
+		return nil, fmt.Errorf("validity %s exceeds maximum of %s", validity, i.maxValidity)
+	}
+
+	if params.StartDate.IsZero() {
+		params.StartDate = i.now()
+	}
+	params.ExpirationDate = params.StartDate.Add(validity)
+
+	metadata := make(map[string]interface{}, len(params.Metadata)+2)
+	for k, v := range params.Metadata {
+		metadata[k] = v
+	}
+	metadata[MetadataTemporary] = true
+	metadata[MetadataExpiresAt] = params.ExpirationDate.UTC().Format(time.RFC3339)
+	params.Metadata = metadata
+
+	issued, err := i.cards.Provision(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("error issuing temporary pass: %w", err)
+	}
+
+	pass := Pass{
+		ID:             issued.GetID(),
+		URL:            issued.GetURL(),
+		CardTemplateID: params.CardTemplateID,
+		FullName:       params.FullName,
+		Email:          params.Email,
+		StartDate:      params.StartDate,
+		ExpirationDate: params.ExpirationDate,
+		State:          StateActive,
+	}
+	for _, card := range issued.GetCards() {
+		pass.CardIDs = append(pass.CardIDs, card.ID)
+	}
+
+	// An untracked pass would never be swept, so don't leave one behind
+	if err := i.registry.Save(pass); err != nil {
+		if deleteErr := i.cards.DeleteAll(ctx, issued); deleteErr != nil {
+			return nil, fmt.Errorf("error recording temporary pass %s: %w (cleanup also failed: %v)", pass.ID, err, deleteErr)
+		}
+		return nil, fmt.Errorf("error recording temporary pass %s: %w", pass.ID, err)
+	}
+
+	return &pass, nil
+}
+
+// SweepResult records what the sweeper did to a single pass
+type SweepResult struct {
+	PassID string `json:"pass_id"`
+	Action Action `json:"action"`
+	Error  string `json:"error,omitempty"`
+}
+
+// SweepReport summarizes a sweep
+type SweepReport struct {
+	Checked int           `json:"checked"`
+	Results []SweepResult `json:"results"`
+}
+
+// Sweep revokes every registered pass whose expiration date, plus the grace
+// period, has passed. Passes already in the target state are skipped and
+// cards that no longer exist are treated as revoked, so Sweep is safe to run
+// repeatedly. The report lists every pass that was changed or failed; the
+// returned error joins any failures.
+func (i *Issuer) Sweep(ctx context.Context) (*SweepReport, error) {
+	passes, err := i.registry.List()
+	if err != nil {
+		return nil, fmt.Errorf("error listing temporary passes: %w", err)
+	}
+
+	report := &SweepReport{Checked: len(passes)}
+	now := i.now()
+
+	var errs []error
+	for _, pass := range passes {
+		if now.Before(pass.ExpirationDate.Add(i.gracePeriod)) {
+			continue
+		}
+		if i.action == ActionSuspend && pass.State == StateSuspended {
+			continue
+		}
+
+		result := SweepResult{PassID: pass.ID, Action: i.action}
+		if err := i.revoke(ctx, pass); err != nil {
+			result.Error = err.Error()
+			errs = append(errs, fmt.Errorf("pass %s: %w", pass.ID, err))
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	return report, errors.Join(errs...)
+}
+
+// revoke applies the sweep action to every card of pass and records the
+// outcome in the registry
+func (i *Issuer) revoke(ctx context.Context, pass Pass) error {
+	op := i.cards.Suspend
+	if i.action == ActionDelete {
+		op = i.cards.Delete
+	}
+
+	gone := 0
+	for _, cardID := range pass.CardIDs {
+		err := op(ctx, cardID)
+		if client.IsNotFound(err) {
+			gone++
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if i.action == ActionDelete || gone == len(pass.CardIDs) {
+		return i.registry.Delete(pass.ID)
+	}
+
+	pass.State = StateSuspended
+	return i.registry.Save(pass)
+}
@@ -0,0 +1,159 @@
+package visitor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Access-Grid/accessgrid-go/client"
+	"github.com/Access-Grid/accessgrid-go/models"
+	"github.com/Access-Grid/accessgrid-go/services"
+)
+
+func setupVisitorTestServer() (*httptest.Server, *services.AccessCardsService, *models.ProvisionParams, *sync.Map) {
+	provisioned := &models.ProvisionParams{}
+	calls := &sync.Map{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if n, ok := calls.Load(r.URL.Path); ok {
+			calls.Store(r.URL.Path, n.(int)+1)
+		} else {
+			calls.Store(r.URL.Path, 1)
+		}
+
+		switch r.URL.Path {
+		case "/v1/key-cards":
+			json.NewDecoder(r.Body).Decode(provisioned)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{
+				"id": "0xv1s1t",
+				"state": "active",
+				"install_url": "https://accessgrid.com/install/0xv1s1t"
+			}`))
+		case "/v1/key-cards/0xv1s1t/suspend":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{}`))
+		case "/v1/key-cards/0xv1s1t/delete":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "not found"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	c, _ := client.NewClient("test-account", "test-secret", client.WithBaseURL(server.URL))
+	return server, services.NewAccessCardsService(c), provisioned, calls
+}
+
+func TestIssuer_Issue(t *testing.T) {
+	server, cards, provisioned, _ := setupVisitorTestServer()
+	defer server.Close()
+
+	registry := NewMemoryRegistry()
+	issuer, err := NewIssuer(cards, registry)
+	if err != nil {
+		t.Fatalf("NewIssuer() error = %v", err)
+	}
+
+	start, _ := time.Parse(time.RFC3339, "2023-01-01T09:00:00Z")
+	params := models.ProvisionParams{
+		CardTemplateID: "0xd3adb00b5",
+		FullName:       "Visitor name",
+		StartDate:      start,
+	}
+
+	pass, err := issuer.Issue(context.Background(), params, 8*time.Hour)
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+
+	if pass.ID != "0xv1s1t" {
+		t.Errorf("Issue() pass.ID = %v, want %v", pass.ID, "0xv1s1t")
+	}
+	wantExpiry := start.Add(8 * time.Hour)
+	if !provisioned.ExpirationDate.Equal(wantExpiry) {
+		t.Errorf("Issue() provisioned ExpirationDate = %v, want %v", provisioned.ExpirationDate, wantExpiry)
+	}
+	if provisioned.Metadata[MetadataTemporary] != true {
+		t.Errorf("Issue() metadata %v = %v, want true", MetadataTemporary, provisioned.Metadata[MetadataTemporary])
+	}
+
+	passes, _ := registry.List()
+	if len(passes) != 1 || passes[0].State != StateActive {
+		t.Errorf("Issue() registry = %+v, want one active pass", passes)
+	}
+
+	if _, err := issuer.Issue(context.Background(), params, 30*24*time.Hour); err == nil {
+		t.Error("Issue() expected error for validity beyond maximum")
+	}
+}
+
+func TestIssuer_SweepSuspend(t *testing.T) {
+	server, cards, _, calls := setupVisitorTestServer()
+	defer server.Close()
+
+	now, _ := time.Parse(time.RFC3339, "2023-01-02T12:00:00Z")
+	registry := NewMemoryRegistry()
+	registry.Save(Pass{ID: "0xv1s1t", CardIDs: []string{"0xv1s1t"}, ExpirationDate: now.Add(-2 * time.Hour), State: StateActive})
+	registry.Save(Pass{ID: "0xgr4c3", CardIDs: []string{"0xgr4c3"}, ExpirationDate: now.Add(-30 * time.Minute), State: StateActive})
+
+	issuer, _ := NewIssuer(cards, registry, WithGracePeriod(time.Hour))
+	issuer.now = func() time.Time { return now }
+
+	for run := 0; run < 2; run++ {
+		report, err := issuer.Sweep(context.Background())
+		if err != nil {
+			t.Fatalf("Sweep() run %d error = %v", run, err)
+		}
+		if report.Checked != 2 {
+			t.Errorf("Sweep() run %d report.Checked = %v, want %v", run, report.Checked, 2)
+		}
+		wantResults := 1
+		if run == 1 {
+			wantResults = 0
+		}
+		if len(report.Results) != wantResults {
+			t.Errorf("Sweep() run %d got %v results, want %v", run, len(report.Results), wantResults)
+		}
+	}
+
+	if n, _ := calls.Load("/v1/key-cards/0xv1s1t/suspend"); n != 1 {
+		t.Errorf("Sweep() suspended %v times, want %v", n, 1)
+	}
+
+	passes, _ := registry.List()
+	for _, pass := range passes {
+		want := StateActive
+		if pass.ID == "0xv1s1t" {
+			want = StateSuspended
+		}
+		if pass.State != want {
+			t.Errorf("Sweep() pass %s state = %v, want %v", pass.ID, pass.State, want)
+		}
+	}
+}
+
+func TestIssuer_SweepDeleteMissingCard(t *testing.T) {
+	server, cards, _, _ := setupVisitorTestServer()
+	defer server.Close()
+
+	registry := NewMemoryRegistry()
+	registry.Save(Pass{ID: "0xv1s1t", CardIDs: []string{"0xv1s1t"}, ExpirationDate: time.Now().Add(-time.Hour), State: StateSuspended})
+
+	issuer, _ := NewIssuer(cards, registry, WithSweepAction(ActionDelete))
+	report, err := issuer.Sweep(context.Background())
+	if err != nil {
+		t.Fatalf("Sweep() error = %v", err)
+	}
+
+	if len(report.Results) != 1 || report.Results[0].Action != ActionDelete {
+		t.Errorf("Sweep() report.Results = %+v, want one delete", report.Results)
+	}
+	if passes, _ := registry.List(); len(passes) != 0 {
+		t.Errorf("Sweep() left %v passes in registry, want 0", len(passes))
+	}
+}
@@ -0,0 +1,75 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Access-Grid/accessgrid-go/models"
+)
+
+// EventType identifies the kind of webhook event
+type EventType = models.WebhookEventType
+
+// Known webhook event types
+const (
+	EventCardCreated     = models.WebhookEventCardCreated
+	EventCardUpdated     = models.WebhookEventCardUpdated
+	EventCardInstalled   = models.WebhookEventCardInstalled
+	EventCardUninstalled = models.WebhookEventCardUninstalled
+	EventCardSuspended   = models.WebhookEventCardSuspended
+	EventCardResumed     = models.WebhookEventCardResumed
+	EventCardUnlinked    = models.WebhookEventCardUnlinked
+	EventCardDeleted     = models.WebhookEventCardDeleted
+
+	EventTemplateCreated = models.WebhookEventTemplateCreated
+	EventTemplateUpdated = models.WebhookEventTemplateUpdated
+	EventTemplateDeleted = models.WebhookEventTemplateDeleted
+
+	EventTest = models.WebhookEventTest
+)
+
+// ErrInvalidPayload is wrapped by the errors from Card and Template when the
+// event data can't be decoded. Handler responds to it with a 400, since
+// retrying the same payload can't succeed.
+var ErrInvalidPayload = errors.New("webhook payload invalid")
+
+// Event is a webhook delivery. Data holds the raw payload, which for card
+// and template events can be decoded with Card and Template.
+type Event struct {
+	ID        string          `json:"id"`
+	Type      EventType       `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Card decodes the event's payload as a card
+func (e *Event) Card() (*models.Card, error) {
+	var card models.Card
+	if err := json.Unmarshal(e.Data, &card); err != nil {
+		return nil, fmt.Errorf("%w: error decoding card from %s event: %w", ErrInvalidPayload, e.Type, err)
+	}
+	return &card, nil
+}
+
+// Template decodes the event's payload as a template
+func (e *Event) Template() (*models.Template, error) {
+	var template models.Template
+	if err := json.Unmarshal(e.Data, &template); err != nil {
+		return nil, fmt.Errorf("%w: error decoding template from %s event: %w", ErrInvalidPayload, e.Type, err)
+	}
+	return &template, nil
+}
+
+// CardEvent is a webhook event carrying a card
+type CardEvent struct {
+	Event
+	Card *models.Card
+}
+
+// TemplateEvent is a webhook event carrying a template
+type TemplateEvent struct {
+	Event
+	Template *models.Template
+}
@@ -0,0 +1,195 @@
+// Package webhook receives AccessGrid webhook deliveries. Handler verifies
+// each delivery's signature, decodes it into a typed event and dispatches it
+// to the functions registered for its event type.
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultMaxBodySize is the largest webhook body Handler accepts unless
+// configured otherwise
+const DefaultMaxBodySize = 1 << 20
+
+// HandlerFunc handles a verified webhook event
+type HandlerFunc func(ctx context.Context, event *Event) error
+
+// CardHandlerFunc handles a verified webhook event carrying a card
+type CardHandlerFunc func(ctx context.Context, event *CardEvent) error
+
+// TemplateHandlerFunc handles a verified webhook event carrying a template
+type TemplateHandlerFunc func(ctx context.Context, event *TemplateEvent) error
+
+// Handler is an http.Handler that receives webhook deliveries. It responds
+// with:
+//   - 405 for methods other than POST
+//   - 413 if the body exceeds the size limit
+//   - 401 if the signature is missing, invalid or outside the tolerance
+//   - 400 if the payload can't be decoded, including a handler error
+//     wrapping ErrInvalidPayload
+//   - 500 if a registered handler returns an error, so the delivery is retried
+//   - 204 otherwise, including for event types with no registered handler
+type Handler struct {
+	secrets     []string
+	tolerance   time.Duration
+	maxBodySize int64
+	now         func() time.Time
+
+	mu       sync.RWMutex
+	handlers map[EventType][]HandlerFunc
+	fallback HandlerFunc
+}
+
+// Option allows for customizing the handler
+type Option func(*Handler)
+
+// WithTolerance sets how far a delivery's timestamp may be from the current
+// time
+func WithTolerance(d time.Duration) Option {
+	return func(h *Handler) {
+		h.tolerance = d
+	}
+}
+
+// WithMaxBodySize sets the largest body accepted, in bytes
+func WithMaxBodySize(n int64) Option {
+	return func(h *Handler) {
+		h.maxBodySize = n
+	}
+}
+
+// WithAdditionalSecrets accepts deliveries signed with any of these secrets
+// as well, so a secret can be rotated without rejecting deliveries in flight
+func WithAdditionalSecrets(secrets ...string) Option {
+	return func(h *Handler) {
+		h.secrets = append(h.secrets, secrets...)
+	}
+}
+
+// NewHandler creates a Handler that verifies deliveries signed with secret
+func NewHandler(secret string, options ...Option) (*Handler, error) {
+	if secret == "" {
+		return nil, errors.New("secret is required")
+	}
+
+	handler := &Handler{
+		secrets:     []string{secret},
+		tolerance:   DefaultTolerance,
+		maxBodySize: DefaultMaxBodySize,
+		now:         time.Now,
+		handlers:    make(map[EventType][]HandlerFunc),
+	}
+
+	// Apply any custom options
+	for _, option := range options {
+		option(handler)
+	}
+
+	return handler, nil
+}
+
+// On registers fn for an event type. Several functions may be registered for
+// the same type; they run in registration order until one fails.
+func (h *Handler) On(eventType EventType, fn HandlerFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.handlers[eventType] = append(h.handlers[eventType], fn)
+}
+
+// OnCard registers fn for a card event type, decoding the card before fn is
+// called
+func (h *Handler) OnCard(eventType EventType, fn CardHandlerFunc) {
+	h.On(eventType, func(ctx context.Context, event *Event) error {
+		card, err := event.Card()
+		if err != nil {
+			return err
+		}
+		return fn(ctx, &CardEvent{Event: *event, Card: card})
+	})
+}
+
+// OnTemplate registers fn for a template event type, decoding the template
+// before fn is called
+func (h *Handler) OnTemplate(eventType EventType, fn TemplateHandlerFunc) {
+	h.On(eventType, func(ctx context.Context, event *Event) error {
+		template, err := event.Template()
+		if err != nil {
+			return err
+		}
+		return fn(ctx, &TemplateEvent{Event: *event, Template: template})
+	})
+}
+
+// OnUnhandled registers fn for event types with no other handler
+func (h *Handler) OnUnhandled(fn HandlerFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.fallback = fn
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, h.maxBodySize))
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			http.Error(w, "payload too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "error reading body", http.StatusBadRequest)
+		return
+	}
+
+	err = Verify(h.secrets, r.Header.Get(SignatureHeader), r.Header.Get(TimestampHeader), body, h.now(), h.tolerance)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var event Event
+	if err := json.Unmarshal(body, &event); err != nil || event.Type == "" {
+		http.Error(w, "invalid webhook payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.dispatch(r.Context(), &event); err != nil {
+		if errors.Is(err, ErrInvalidPayload) {
+			http.Error(w, "invalid webhook payload", http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "error handling webhook", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) dispatch(ctx context.Context, event *Event) error {
+	h.mu.RLock()
+	handlers := h.handlers[event.Type]
+	fallback := h.fallback
+	h.mu.RUnlock()
+
+	if len(handlers) == 0 && fallback != nil {
+		handlers = []HandlerFunc{fallback}
+	}
+	for _, fn := range handlers {
+		if err := fn(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testNow, _ = time.Parse(time.RFC3339, "2023-01-01T12:00:00Z")
+
+func newTestHandler(t *testing.T, options ...Option) *Handler {
+	t.Helper()
+	handler, err := NewHandler("test-secret", options...)
+	if err != nil {
+		t.Fatalf("NewHandler() error = %v", err)
+	}
+	handler.now = func() time.Time { return testNow }
+	return handler
+}
+
+func signedRequest(secret string, sentAt time.Time, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+	req.Header.Set(TimestampHeader, strconv.FormatInt(sentAt.Unix(), 10))
+	req.Header.Set(SignatureHeader, Sign(secret, sentAt, []byte(body)))
+	return req
+}
+
+const suspendedBody = `{
+	"id": "whevt_123",
+	"type": "card.suspended",
+	"created_at": "2023-01-01T12:00:00Z",
+	"data": {"id": "0xc4rd1d", "state": "suspended"}
+}`
+
+func TestHandler_DispatchesCardEvent(t *testing.T) {
+	handler := newTestHandler(t)
+
+	var got *CardEvent
+	handler.OnCard(EventCardSuspended, func(ctx context.Context, event *CardEvent) error {
+		got = event
+		return nil
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, signedRequest("test-secret", testNow, suspendedBody))
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("ServeHTTP() status = %v, want %v", rec.Code, http.StatusNoContent)
+	}
+	if got == nil {
+		t.Fatal("ServeHTTP() did not dispatch event")
+	}
+	if got.ID != "whevt_123" || got.Card.ID != "0xc4rd1d" || got.Card.State != "suspended" {
+		t.Errorf("ServeHTTP() dispatched %+v, card %+v", got.Event, got.Card)
+	}
+}
+
+func TestHandler_StatusCodes(t *testing.T) {
+	tests := []struct {
+		name    string
+		request func() *http.Request
+		want    int
+	}{
+		{
+			name: "Wrong method",
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/webhooks", nil)
+			},
+			want: http.StatusMethodNotAllowed,
+		},
+		{
+			name: "Missing signature",
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(suspendedBody))
+			},
+			want: http.StatusUnauthorized,
+		},
+		{
+			name: "Wrong secret",
+			request: func() *http.Request {
+				return signedRequest("other-secret", testNow, suspendedBody)
+			},
+			want: http.StatusUnauthorized,
+		},
+		{
+			name: "Tampered body",
+			request: func() *http.Request {
+				req := signedRequest("test-secret", testNow, suspendedBody)
+				req.Body = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(strings.Replace(suspendedBody, "suspended", "resumed", 1))).Body
+				return req
+			},
+			want: http.StatusUnauthorized,
+		},
+		{
+			name: "Replayed delivery",
+			request: func() *http.Request {
+				return signedRequest("test-secret", testNow.Add(-time.Hour), suspendedBody)
+			},
+			want: http.StatusUnauthorized,
+		},
+		{
+			name: "Invalid payload",
+			request: func() *http.Request {
+				return signedRequest("test-secret", testNow, `not json`)
+			},
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "Too large",
+			request: func() *http.Request {
+				return signedRequest("test-secret", testNow, `{"data": "`+strings.Repeat("x", 2048)+`"}`)
+			},
+			want: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name: "Handler error",
+			request: func() *http.Request {
+				return signedRequest("test-secret", testNow, suspendedBody)
+			},
+			want: http.StatusInternalServerError,
+		},
+		{
+			name: "Undecodable card",
+			request: func() *http.Request {
+				return signedRequest("test-secret", testNow, `{"id": "whevt_1", "type": "card.updated", "data": "0xc4rd1d"}`)
+			},
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "Unhandled type",
+			request: func() *http.Request {
+				return signedRequest("test-secret", testNow, `{"id": "whevt_1", "type": "template.created", "data": {}}`)
+			},
+			want: http.StatusNoContent,
+		},
+		{
+			name: "Rotated secret",
+			request: func() *http.Request {
+				return signedRequest("old-secret", testNow, `{"id": "whevt_1", "type": "template.created", "data": {}}`)
+			},
+			want: http.StatusNoContent,
+		},
+	}
+
+	handler := newTestHandler(t, WithMaxBodySize(1024), WithAdditionalSecrets("old-secret"))
+	handler.On(EventCardSuspended, func(ctx context.Context, event *Event) error {
+		return errors.New("downstream unavailable")
+	})
+	handler.OnCard(EventCardUpdated, func(ctx context.Context, event *CardEvent) error {
+		t.Error("OnCard() handler called for an undecodable card")
+		return nil
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, tt.request())
+			if rec.Code != tt.want {
+				t.Errorf("ServeHTTP() status = %v, want %v (%s)", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_OnUnhandled(t *testing.T) {
+	handler := newTestHandler(t)
+
+	var got EventType
+	handler.OnUnhandled(func(ctx context.Context, event *Event) error {
+		got = event.Type
+		return nil
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, signedRequest("test-secret", testNow, `{"id": "whevt_1", "type": "card.future_event", "data": {}}`))
+
+	if got != "card.future_event" {
+		t.Errorf("OnUnhandled() got type %v, want %v", got, "card.future_event")
+	}
+}
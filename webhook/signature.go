@@ -0,0 +1,71 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Headers carrying the webhook signature
+const (
+	SignatureHeader = "X-AccessGrid-Signature"
+	TimestampHeader = "X-AccessGrid-Timestamp"
+)
+
+// DefaultTolerance is how far a webhook's timestamp may be from the current
+// time before it is rejected as a possible replay
+const DefaultTolerance = 5 * time.Minute
+
+// Signature verification errors
+var (
+	ErrMissingSignature = errors.New("webhook signature missing")
+	ErrInvalidSignature = errors.New("webhook signature invalid")
+	ErrInvalidTimestamp = errors.New("webhook timestamp invalid")
+	ErrTimestampExpired = errors.New("webhook timestamp outside tolerance")
+)
+
+// Sign computes the signature for a webhook payload: the hex-encoded
+// HMAC-SHA256, keyed with secret, of the Unix timestamp, a period, and the
+// raw body
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	h.Write([]byte{'.'})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Verify checks a webhook's signature and timestamp headers against body.
+// The signature is compared in constant time, and timestamps further than
+// tolerance from now are rejected. Any of secrets may match, which allows
+// secrets to be rotated without dropping deliveries.
+func Verify(secrets []string, signature, timestamp string, body []byte, now time.Time, tolerance time.Duration) error {
+	if signature == "" || timestamp == "" {
+		return ErrMissingSignature
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: %q", ErrInvalidTimestamp, timestamp)
+	}
+	sent := time.Unix(unix, 0)
+	if skew := now.Sub(sent); skew > tolerance || skew < -tolerance {
+		return ErrTimestampExpired
+	}
+
+	given, err := hex.DecodeString(signature)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	for _, secret := range secrets {
+		expected, _ := hex.DecodeString(Sign(secret, sent, body))
+		if hmac.Equal(given, expected) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}